@@ -1,75 +1,487 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
+	"container/heap"
+	"errors"
+	"flag"
 	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
 )
 
-// MergeSorter is a structure for sorting arrays using Merge Sort.
-type MergeSorter struct {
-	array []int
+const (
+	// defaultParallelThreshold is the subarray length above which the two halves are sorted on separate goroutines.
+	defaultParallelThreshold = 1 << 13
+	// insertionSortThreshold is the subarray length below which insertion sort replaces further splitting.
+	insertionSortThreshold = 12
+	// bytesPerBufferedInt is the memory one buffered integer costs while a run is sorted:
+	// the run itself, the copy returned by Sort and the merge buffer.
+	bytesPerBufferedInt = 3 * 8
+	// maxMergeFanIn is the largest number of runs merged at once, which bounds the open files.
+	maxMergeFanIn = 64
+	// minMergeBufferSize and maxMergeBufferSize bound the buffer of each run reader and of the merge output.
+	minMergeBufferSize = 64
+	maxMergeBufferSize = 64 << 10
+)
+
+// MergeSorter is a structure for sorting slices of any element type using a stable Merge Sort.
+type MergeSorter[T any] struct {
+	array             []T
+	compare           func(a, b T) int
+	parallelThreshold int
 }
 
-// NewMergeSorter initializes a new instance of MergeSorter with the provided array.
-func NewMergeSorter(array []int) *MergeSorter {
+// NewMergeSorter initializes a new instance of MergeSorter with the provided array and comparison function.
+// The comparison function returns a negative number when a < b, zero when a == b and a positive number when a > b.
+func NewMergeSorter[T any](array []T, compare func(a, b T) int) *MergeSorter[T] {
 	if array == nil {
 		panic("Input array cannot be nil")
 	}
-	return &MergeSorter{array: array}
+	if compare == nil {
+		panic("Comparison function cannot be nil")
+	}
+	return &MergeSorter[T]{array: array, compare: compare, parallelThreshold: defaultParallelThreshold}
+}
+
+// SetParallelThreshold sets the subarray length above which halves are sorted in parallel.
+// A threshold of zero or less disables parallel sorting.
+func (ms *MergeSorter[T]) SetParallelThreshold(threshold int) {
+	ms.parallelThreshold = threshold
 }
 
 // Sort performs the Merge Sort on the array without modifying the original array.
-func (ms *MergeSorter) Sort() []int {
-	if len(ms.array) <= 1 {
-		return ms.array
+// Equal elements keep their original relative order.
+func (ms *MergeSorter[T]) Sort() []T {
+	sorted := append([]T(nil), ms.array...)
+	if len(sorted) <= 1 {
+		return sorted
 	}
-	return ms.mergeSort(ms.array)
+
+	// A single buffer is allocated once and shared by every level of the recursion.
+	buffer := make([]T, len(sorted))
+	ms.mergeSort(sorted, buffer)
+	return sorted
 }
 
-// mergeSort recursively splits and sorts the array.
-func (ms *MergeSorter) mergeSort(arr []int) []int {
-	// Base case: if the array has 1 or 0 elements, it is already sorted.
-	if len(arr) <= 1 {
-		return arr
+// mergeSort recursively splits and sorts arr in place, using buffer (of the same length) as scratch space.
+func (ms *MergeSorter[T]) mergeSort(arr, buffer []T) {
+	// Base case: small subarrays are sorted directly.
+	if len(arr) <= insertionSortThreshold {
+		ms.insertionSort(arr)
+		return
 	}
 
-	// Split the array into two halves
+	// Split the array into two halves and sort them, in parallel if the subarray is large enough.
 	mid := len(arr) / 2
-	left := ms.mergeSort(arr[:mid])
-	right := ms.mergeSort(arr[mid:])
+	if ms.parallelThreshold > 0 && len(arr) > ms.parallelThreshold {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ms.mergeSort(arr[:mid], buffer[:mid])
+		}()
+		ms.mergeSort(arr[mid:], buffer[mid:])
+		wg.Wait()
+	} else {
+		ms.mergeSort(arr[:mid], buffer[:mid])
+		ms.mergeSort(arr[mid:], buffer[mid:])
+	}
 
-	// Merge the sorted halves
-	return ms.merge(left, right)
+	// Merge the sorted halves.
+	ms.merge(arr, buffer, mid)
 }
 
-// merge combines two sorted slices into a single sorted slice.
-func (ms *MergeSorter) merge(left, right []int) []int {
-	merged := make([]int, 0, len(left)+len(right))
-	i, j := 0, 0
+// merge combines the sorted halves arr[:mid] and arr[mid:] in place.
+// The left half is copied into buffer, then both halves are merged back into arr.
+func (ms *MergeSorter[T]) merge(arr, buffer []T, mid int) {
+	// Already in order: nothing to merge.
+	if ms.compare(arr[mid-1], arr[mid]) <= 0 {
+		return
+	}
+
+	left := buffer[:mid]
+	copy(left, arr[:mid])
+	i, j, k := 0, mid, 0
 
-	// Compare elements from both halves and add the smaller one to merged slice.
-	for i < len(left) && j < len(right) {
-		if left[i] < right[j] {
-			merged = append(merged, left[i])
+	// Compare elements from both halves and write the smaller one back.
+	// Ties are taken from the left half, which keeps the sort stable.
+	for i < len(left) && j < len(arr) {
+		if ms.compare(left[i], arr[j]) <= 0 {
+			arr[k] = left[i]
 			i++
 		} else {
-			merged = append(merged, right[j])
+			arr[k] = arr[j]
 			j++
 		}
+		k++
 	}
 
-	// Append remaining elements from left or right slice.
-	if i < len(left) {
-		merged = append(merged, left[i:]...)
-	} else {
-		merged = append(merged, right[j:]...)
+	// Remaining elements of the right half are already in place; copy what is left of the left half.
+	copy(arr[k:], left[i:])
+}
+
+// insertionSort sorts a small subarray in place, keeping equal elements in order.
+func (ms *MergeSorter[T]) insertionSort(arr []T) {
+	for i := 1; i < len(arr); i++ {
+		for j := i; j > 0 && ms.compare(arr[j-1], arr[j]) > 0; j-- {
+			arr[j-1], arr[j] = arr[j], arr[j-1]
+		}
+	}
+}
+
+// ExternalSort sorts a file of integers, one per line, that may be larger than memory.
+// The input is cut into runs that fit in memoryBudget bytes, each run is sorted with MergeSorter
+// and spilled to a temporary file, and the runs are combined with a heap-based k-way merge into outputPath.
+// Each merge reads at most maxMergeFanIn runs through buffers that, with the output buffer, fit in the
+// budget; when there are more runs, they are merged in passes into longer runs first.
+func ExternalSort(inputPath, outputPath string, memoryBudget int) error {
+	runLength := memoryBudget / bytesPerBufferedInt
+	if runLength < 1 {
+		return errors.New("memory budget is too small to hold a single integer")
+	}
+	bufferSize := min(max(memoryBudget/(maxMergeFanIn+1), minMergeBufferSize), maxMergeBufferSize)
+	fanIn := min(memoryBudget/bufferSize-1, maxMergeFanIn)
+	if fanIn < 2 {
+		return errors.New("memory budget is too small to merge two runs")
+	}
+
+	runPaths, err := createSortedRuns(inputPath, runLength)
+	// Temporary run files, including those of intermediate passes, are removed whether or not the sort
+	// succeeds; files already removed after their pass are skipped by os.Remove.
+	temporary := runPaths
+	defer func() {
+		for _, path := range temporary {
+			os.Remove(path)
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	// Merge groups of fanIn runs into longer runs until a single merge can produce the output.
+	for len(runPaths) > fanIn {
+		var merged []string
+		for start := 0; start < len(runPaths); start += fanIn {
+			runFile, err := os.CreateTemp("", "mergesort-run-*.txt")
+			if err != nil {
+				return err
+			}
+			runFile.Close()
+			temporary = append(temporary, runFile.Name())
+			merged = append(merged, runFile.Name())
+
+			group := runPaths[start:min(start+fanIn, len(runPaths))]
+			if err := mergeRuns(group, runFile.Name(), bufferSize); err != nil {
+				return err
+			}
+			for _, path := range group {
+				os.Remove(path)
+			}
+		}
+		runPaths = merged
+	}
+
+	return mergeRuns(runPaths, outputPath, bufferSize)
+}
+
+// createSortedRuns reads the input in chunks of runLength integers, sorts each chunk
+// and writes it to its own temporary file. It returns the paths of the files created so far,
+// even when an error occurs, so the caller can clean them up.
+func createSortedRuns(inputPath string, runLength int) ([]string, error) {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var runPaths []string
+	run := make([]int, 0, runLength)
+
+	// flush sorts the buffered run and spills it to a temporary file.
+	flush := func() error {
+		if len(run) == 0 {
+			return nil
+		}
+		sorted := NewMergeSorter(run, cmp.Compare[int]).Sort()
+		runFile, err := os.CreateTemp("", "mergesort-run-*.txt")
+		if err != nil {
+			return err
+		}
+		runPaths = append(runPaths, runFile.Name())
+		if err := writeIntegers(runFile, sorted); err != nil {
+			runFile.Close()
+			return err
+		}
+		run = run[:0]
+		return runFile.Close()
+	}
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		num, err := strconv.Atoi(line)
+		if err != nil {
+			return runPaths, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		run = append(run, num)
+		if len(run) == runLength {
+			if err := flush(); err != nil {
+				return runPaths, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return runPaths, err
+	}
+	return runPaths, flush()
+}
+
+// writeIntegers writes the integers to the file, one per line.
+func writeIntegers(file *os.File, values []int) error {
+	writer := bufio.NewWriter(file)
+	for _, value := range values {
+		if _, err := writer.WriteString(strconv.Itoa(value) + "\n"); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// runHead is the smallest unread value of one sorted run.
+type runHead struct {
+	value int
+	run   int
+}
+
+// runHeap is a min-heap of run heads ordered by value, then by run index so that the merge is stable.
+type runHeap []runHead
+
+func (h runHeap) Len() int { return len(h) }
+func (h runHeap) Less(i, j int) bool {
+	if h[i].value != h[j].value {
+		return h[i].value < h[j].value
 	}
+	return h[i].run < h[j].run
+}
+func (h runHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)   { *h = append(*h, x.(runHead)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	head := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return head
+}
 
-	return merged
+// mergeRuns merges the sorted run files into outputPath, keeping one value per run in a min-heap.
+// Every run and the output are read and written through buffers of bufferSize bytes, and each run
+// file is closed as soon as it is exhausted.
+func mergeRuns(runPaths []string, outputPath string, bufferSize int) error {
+	files := make([]*os.File, len(runPaths))
+	// Files still open when the merge stops early are closed on return.
+	defer func() {
+		for _, file := range files {
+			if file != nil {
+				file.Close()
+			}
+		}
+	}()
+	scanners := make([]*bufio.Scanner, len(runPaths))
+	for i, path := range runPaths {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		files[i] = file
+		scanners[i] = bufio.NewScanner(file)
+		scanners[i].Buffer(make([]byte, 0, bufferSize), bufferSize)
+	}
+
+	// next reads the following value of a run, reporting false and closing the run when it is exhausted.
+	next := func(run int) (int, bool, error) {
+		if !scanners[run].Scan() {
+			if err := scanners[run].Err(); err != nil {
+				return 0, false, err
+			}
+			err := files[run].Close()
+			files[run] = nil
+			return 0, false, err
+		}
+		value, err := strconv.Atoi(scanners[run].Text())
+		return value, err == nil, err
+	}
+
+	// Seed the heap with the first value of every run.
+	h := &runHeap{}
+	for run := range scanners {
+		value, ok, err := next(run)
+		if err != nil {
+			return err
+		}
+		if ok {
+			*h = append(*h, runHead{value: value, run: run})
+		}
+	}
+	heap.Init(h)
+
+	output, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriterSize(output, bufferSize)
+
+	// Repeatedly emit the smallest head and replace it with the next value of the same run.
+	for h.Len() > 0 {
+		head := (*h)[0]
+		if _, err := writer.WriteString(strconv.Itoa(head.value) + "\n"); err != nil {
+			output.Close()
+			return err
+		}
+		value, ok, err := next(head.run)
+		if err != nil {
+			output.Close()
+			return err
+		}
+		if ok {
+			(*h)[0].value = value
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+// parseByteSize parses a memory size such as "4096", "512KB", "64MB" or "2GB".
+func parseByteSize(argument string) (int, error) {
+	size := strings.ToUpper(strings.TrimSpace(argument))
+	multiplier := 1
+	for _, unit := range []struct {
+		suffix string
+		factor int
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(size, unit.suffix) {
+			size = strings.TrimSuffix(size, unit.suffix)
+			multiplier = unit.factor
+			break
+		}
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(size))
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid memory size %q", argument)
+	}
+	return value * multiplier, nil
+}
+
+// runBenchmarks compares MergeSorter with sort.SliceStable and slices.SortStableFunc on random integers.
+// Every variant sorts a fresh copy of the input on each iteration.
+func runBenchmarks() {
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []int{1000, 100000, 1000000} {
+		input := make([]int, size)
+		for i := range input {
+			input[i] = rng.Intn(size)
+		}
+
+		variants := []struct {
+			name string
+			sort func([]int)
+		}{
+			{"MergeSorter (sequential)", func(arr []int) {
+				sorter := NewMergeSorter(arr, cmp.Compare[int])
+				sorter.SetParallelThreshold(0)
+				sorter.Sort()
+			}},
+			{"MergeSorter (parallel)", func(arr []int) {
+				NewMergeSorter(arr, cmp.Compare[int]).Sort()
+			}},
+			{"sort.SliceStable", func(arr []int) {
+				arr = append([]int(nil), arr...)
+				sort.SliceStable(arr, func(i, j int) bool { return arr[i] < arr[j] })
+			}},
+			{"slices.SortStableFunc", func(arr []int) {
+				arr = append([]int(nil), arr...)
+				slices.SortStableFunc(arr, cmp.Compare[int])
+			}},
+		}
+
+		fmt.Printf("n = %d\n", size)
+		for _, variant := range variants {
+			result := testing.Benchmark(func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					variant.sort(input)
+				}
+			})
+			fmt.Printf("  %-26s %14d ns/op %12d B/op\n", variant.name, result.NsPerOp(), result.AllocedBytesPerOp())
+		}
+	}
 }
 
 func main() {
+	bench := flag.Bool("bench", false, "benchmark MergeSorter against the standard library")
+	inputPath := flag.String("input", "", "external sort mode: file of integers, one per line, to sort")
+	outputPath := flag.String("output", "", "external sort mode: file to write the sorted integers to")
+	memory := flag.String("memory", "64MB", "external sort mode: memory budget for in-memory runs, e.g. 512KB or 64MB")
+	flag.Parse()
+
+	// External sort mode: sort a file that may not fit in memory and exit.
+	if *inputPath != "" || *outputPath != "" {
+		if *inputPath == "" || *outputPath == "" {
+			fmt.Println("Error: both -input and -output are required for external sort")
+			os.Exit(2)
+		}
+		memoryBudget, err := parseByteSize(*memory)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(2)
+		}
+		if err := ExternalSort(*inputPath, *outputPath, memoryBudget); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Sorted %s into %s\n", *inputPath, *outputPath)
+		return
+	}
+
 	arr := []int{38, 27, 43, 3, 9, 82, 10}
-	mergeSorter := NewMergeSorter(arr)
+	mergeSorter := NewMergeSorter(arr, cmp.Compare[int])
 	sortedArr := mergeSorter.Sort()
 	fmt.Println("Sorted array:", sortedArr)
+
+	// Stability: records with equal keys keep their input order.
+	type record struct {
+		key   int
+		label string
+	}
+	records := []record{{2, "a"}, {1, "b"}, {2, "c"}, {1, "d"}, {0, "e"}, {2, "f"}}
+	byKey := func(a, b record) int { return cmp.Compare(a.key, b.key) }
+	fmt.Println("Sorted records:", NewMergeSorter(records, byKey).Sort())
+
+	// A large input crosses the parallel threshold; the result must match the standard library's stable sort.
+	large := make([]record, 200000)
+	for i := range large {
+		large[i] = record{key: rand.Intn(1000), label: fmt.Sprint(i)}
+	}
+	expected := append([]record(nil), large...)
+	slices.SortStableFunc(expected, byKey)
+	fmt.Println("Parallel sort matches slices.SortStableFunc:", slices.Equal(NewMergeSorter(large, byKey).Sort(), expected))
+
+	if *bench {
+		runBenchmarks()
+	}
 }
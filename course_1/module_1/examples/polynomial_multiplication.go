@@ -0,0 +1,312 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// karatsubaPolynomialCutoff is the operand length below which the recursion switches to naive convolution.
+const karatsubaPolynomialCutoff = 32
+
+// Ring describes the coefficient arithmetic used by the polynomial operations.
+// Implementations must be comparable so that two polynomials can check they share a ring.
+type Ring interface {
+	// Normalize maps an arbitrary integer to its canonical representative in the ring.
+	Normalize(a int64) int64
+	Add(a, b int64) int64
+	Sub(a, b int64) int64
+	Mul(a, b int64) int64
+	Name() string
+}
+
+// IntegerRing is the ring of machine integers; coefficients are not reduced.
+type IntegerRing struct{}
+
+func (IntegerRing) Normalize(a int64) int64 { return a }
+func (IntegerRing) Add(a, b int64) int64    { return a + b }
+func (IntegerRing) Sub(a, b int64) int64    { return a - b }
+func (IntegerRing) Mul(a, b int64) int64    { return a * b }
+func (IntegerRing) Name() string            { return "Z" }
+
+// ModularRing is the ring of integers modulo a prime; coefficients are kept in [0, Modulus).
+// The modulus must be below 2^31 so that products of two coefficients fit in an int64.
+type ModularRing struct {
+	Modulus int64
+}
+
+func (r ModularRing) Normalize(a int64) int64 {
+	a %= r.Modulus
+	if a < 0 {
+		a += r.Modulus
+	}
+	return a
+}
+func (r ModularRing) Add(a, b int64) int64 { return (a + b) % r.Modulus }
+func (r ModularRing) Sub(a, b int64) int64 { return (a - b + r.Modulus) % r.Modulus }
+func (r ModularRing) Mul(a, b int64) int64 { return a * b % r.Modulus }
+func (r ModularRing) Name() string         { return fmt.Sprintf("Z/%dZ", r.Modulus) }
+
+// GF2Ring is the field with two elements; addition is XOR and multiplication is AND.
+type GF2Ring struct{}
+
+func (GF2Ring) Normalize(a int64) int64 { return a & 1 }
+func (GF2Ring) Add(a, b int64) int64    { return a ^ b }
+func (GF2Ring) Sub(a, b int64) int64    { return a ^ b }
+func (GF2Ring) Mul(a, b int64) int64    { return a & b }
+func (GF2Ring) Name() string            { return "GF(2)" }
+
+// Polynomial is a polynomial over a Ring, stored as coefficients in increasing order of degree.
+// The coefficient slice never has trailing zeros, so the zero polynomial has no coefficients.
+type Polynomial struct {
+	ring         Ring
+	coefficients []int64
+}
+
+// NewPolynomial creates a polynomial over the ring from coefficients given in increasing order of degree.
+// The input slice is copied and every coefficient is normalized.
+func NewPolynomial(ring Ring, coefficients []int64) *Polynomial {
+	normalized := make([]int64, len(coefficients))
+	for i, c := range coefficients {
+		normalized[i] = ring.Normalize(c)
+	}
+	return &Polynomial{ring: ring, coefficients: trimPolynomial(normalized)}
+}
+
+// Degree returns the degree of the polynomial, or -1 for the zero polynomial.
+func (p *Polynomial) Degree() int {
+	return len(p.coefficients) - 1
+}
+
+// Coefficient returns the coefficient of x^i, which is zero above the degree.
+func (p *Polynomial) Coefficient(i int) int64 {
+	if i < 0 || i >= len(p.coefficients) {
+		return 0
+	}
+	return p.coefficients[i]
+}
+
+// Coefficients returns a copy of the coefficients in increasing order of degree.
+func (p *Polynomial) Coefficients() []int64 {
+	return append([]int64(nil), p.coefficients...)
+}
+
+// Evaluate computes p(x) in the polynomial's ring using Horner's rule.
+func (p *Polynomial) Evaluate(x int64) int64 {
+	x = p.ring.Normalize(x)
+	result := int64(0)
+	for i := len(p.coefficients) - 1; i >= 0; i-- {
+		result = p.ring.Add(p.ring.Mul(result, x), p.coefficients[i])
+	}
+	return result
+}
+
+// Equal reports whether two polynomials are over the same ring and have the same coefficients.
+func (p *Polynomial) Equal(q *Polynomial) bool {
+	if p.ring != q.ring || len(p.coefficients) != len(q.coefficients) {
+		return false
+	}
+	for i := range p.coefficients {
+		if p.coefficients[i] != q.coefficients[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Multiply returns p * q computed with the Karatsuba recursion.
+func (p *Polynomial) Multiply(q *Polynomial) (*Polynomial, error) {
+	if p.ring != q.ring {
+		return nil, errors.New("polynomials must be defined over the same ring")
+	}
+	if len(p.coefficients) == 0 || len(q.coefficients) == 0 {
+		return &Polynomial{ring: p.ring}, nil
+	}
+	product := karatsubaPolynomial(p.ring, p.coefficients, q.coefficients)
+	return &Polynomial{ring: p.ring, coefficients: trimPolynomial(product)}, nil
+}
+
+// NaiveMultiply returns p * q computed with the quadratic convolution; it is the reference for Multiply.
+func (p *Polynomial) NaiveMultiply(q *Polynomial) (*Polynomial, error) {
+	if p.ring != q.ring {
+		return nil, errors.New("polynomials must be defined over the same ring")
+	}
+	if len(p.coefficients) == 0 || len(q.coefficients) == 0 {
+		return &Polynomial{ring: p.ring}, nil
+	}
+	product := naiveConvolution(p.ring, p.coefficients, q.coefficients)
+	return &Polynomial{ring: p.ring, coefficients: trimPolynomial(product)}, nil
+}
+
+// String formats the polynomial with the highest degree first, e.g. "3x^2 + 2x + 1".
+func (p *Polynomial) String() string {
+	if len(p.coefficients) == 0 {
+		return "0"
+	}
+	var terms []string
+	for i := len(p.coefficients) - 1; i >= 0; i-- {
+		c := p.coefficients[i]
+		if c == 0 {
+			continue
+		}
+		switch {
+		case i == 0:
+			terms = append(terms, fmt.Sprintf("%d", c))
+		case c == 1 && i == 1:
+			terms = append(terms, "x")
+		case c == 1:
+			terms = append(terms, fmt.Sprintf("x^%d", i))
+		case i == 1:
+			terms = append(terms, fmt.Sprintf("%dx", c))
+		default:
+			terms = append(terms, fmt.Sprintf("%dx^%d", c, i))
+		}
+	}
+	return strings.Join(terms, " + ")
+}
+
+// karatsubaPolynomial multiplies two non-empty coefficient slices with the same divide-and-conquer
+// scheme as the integer Karatsuba algorithm: three half-size products instead of four.
+// The result always has len(a)+len(b)-1 coefficients.
+func karatsubaPolynomial(ring Ring, a, b []int64) []int64 {
+	if len(a) < karatsubaPolynomialCutoff || len(b) < karatsubaPolynomialCutoff {
+		return naiveConvolution(ring, a, b)
+	}
+
+	// Split both operands at the same power x^m: a = a0 + x^m * a1, b = b0 + x^m * b1.
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	m := n / 2
+	a0, a1 := splitPolynomial(a, m)
+	b0, b1 := splitPolynomial(b, m)
+
+	// Base case of the split: one operand fits entirely in the low half.
+	if len(a1) == 0 || len(b1) == 0 {
+		return naiveConvolution(ring, a, b)
+	}
+
+	// Recursively calculate three products.
+	z0 := karatsubaPolynomial(ring, a0, b0)
+	z2 := karatsubaPolynomial(ring, a1, b1)
+	z1 := karatsubaPolynomial(ring, addCoefficients(ring, a0, a1), addCoefficients(ring, b0, b1))
+
+	// Middle term: z1 - z0 - z2.
+	for i, c := range z0 {
+		z1[i] = ring.Sub(z1[i], c)
+	}
+	for i, c := range z2 {
+		z1[i] = ring.Sub(z1[i], c)
+	}
+
+	// Combine: result = z0 + z1 * x^m + z2 * x^(2m).
+	result := make([]int64, len(a)+len(b)-1)
+	for i, c := range z0 {
+		result[i] = ring.Add(result[i], c)
+	}
+	for i, c := range z1 {
+		if i+m < len(result) {
+			result[i+m] = ring.Add(result[i+m], c)
+		}
+	}
+	for i, c := range z2 {
+		result[i+2*m] = ring.Add(result[i+2*m], c)
+	}
+	return result
+}
+
+// naiveConvolution multiplies two non-empty coefficient slices term by term.
+func naiveConvolution(ring Ring, a, b []int64) []int64 {
+	result := make([]int64, len(a)+len(b)-1)
+	for i, x := range a {
+		if x == 0 {
+			continue
+		}
+		for j, y := range b {
+			result[i+j] = ring.Add(result[i+j], ring.Mul(x, y))
+		}
+	}
+	return result
+}
+
+// splitPolynomial splits coefficients into the part below x^m and the part from x^m upwards.
+func splitPolynomial(coefficients []int64, m int) ([]int64, []int64) {
+	if len(coefficients) <= m {
+		return coefficients, nil
+	}
+	return coefficients[:m], coefficients[m:]
+}
+
+// addCoefficients adds two coefficient slices of possibly different lengths into a new slice.
+func addCoefficients(ring Ring, a, b []int64) []int64 {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	sum := append([]int64(nil), a...)
+	for i, c := range b {
+		sum[i] = ring.Add(sum[i], c)
+	}
+	return sum
+}
+
+// trimPolynomial removes trailing zero coefficients so the slice length matches the degree.
+func trimPolynomial(coefficients []int64) []int64 {
+	for len(coefficients) > 0 && coefficients[len(coefficients)-1] == 0 {
+		coefficients = coefficients[:len(coefficients)-1]
+	}
+	return coefficients
+}
+
+// randomPolynomial builds a polynomial of the given length with coefficients in [-limit, limit].
+func randomPolynomial(rng *rand.Rand, ring Ring, length int, limit int64) *Polynomial {
+	coefficients := make([]int64, length)
+	for i := range coefficients {
+		coefficients[i] = rng.Int63n(2*limit+1) - limit
+	}
+	return NewPolynomial(ring, coefficients)
+}
+
+func main() {
+	// Small example over the integers: (1 + 2x + 3x^2) * (4 + 5x) = 4 + 13x + 22x^2 + 15x^3.
+	p := NewPolynomial(IntegerRing{}, []int64{1, 2, 3})
+	q := NewPolynomial(IntegerRing{}, []int64{4, 5})
+	product, err := p.Multiply(q)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("(%v) * (%v) = %v\n", p, q, product)
+	fmt.Printf("Degree: %d, value at x = 2: %d\n", product.Degree(), product.Evaluate(2))
+
+	// Mixing rings is rejected.
+	if _, err := p.Multiply(NewPolynomial(GF2Ring{}, []int64{1, 1})); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// Check Karatsuba against naive convolution in every ring, including unequal lengths.
+	rng := rand.New(rand.NewSource(1))
+	rings := []struct {
+		ring  Ring
+		limit int64
+	}{
+		{IntegerRing{}, 1000},
+		{ModularRing{Modulus: 998244353}, 1 << 40},
+		{GF2Ring{}, 1},
+	}
+	for _, r := range rings {
+		matches := true
+		for _, lengths := range [][2]int{{1, 1}, {40, 40}, {257, 100}, {1000, 1000}, {5, 700}} {
+			a := randomPolynomial(rng, r.ring, lengths[0], r.limit)
+			b := randomPolynomial(rng, r.ring, lengths[1], r.limit)
+			fast, _ := a.Multiply(b)
+			slow, _ := a.NaiveMultiply(b)
+			x := rng.Int63n(100)
+			if !fast.Equal(slow) || fast.Evaluate(x) != r.ring.Mul(a.Evaluate(x), b.Evaluate(x)) {
+				matches = false
+			}
+		}
+		fmt.Printf("Karatsuba matches naive convolution over %s: %v\n", r.ring.Name(), matches)
+	}
+}
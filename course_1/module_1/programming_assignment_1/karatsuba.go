@@ -1,64 +1,531 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"math/big"
+	"math/bits"
+	"math/rand"
+	"strings"
+	"testing"
 )
 
-// KaratsubaMultiplier - struct containing static methods to perform multiplication using the Karatsuba algorithm.
-type KaratsubaMultiplier struct{}
+// Tuned operand sizes, in bits, below which each algorithm falls back to schoolbook multiplication.
+// The values were picked where each recursion starts to beat schoolbookMultiply on a typical amd64 machine.
+const (
+	defaultKaratsubaCutoff = 2048
+	defaultToomCook3Cutoff = 3072
+	defaultNTTCutoff       = 16384
+)
+
+// Smallest cutoffs, in bits, at which the recursions still shrink their operands. Below them a split can
+// return parts as long as the operands, and the recursion never ends; smaller cutoffs are raised to these.
+const (
+	minKaratsubaCutoff = 2
+	minToomCook3Cutoff = 4
+)
 
-// KaratsubaMultiply - static method to initiate multiplication using the Karatsuba algorithm for big.Int values.
-func (KaratsubaMultiplier) KaratsubaMultiply(x, y *big.Int) *big.Int {
-	return karatsuba(x, y)
+// Multiplier - interface implemented by every large-integer multiplication algorithm in this file.
+type Multiplier interface {
+	// Multiply returns the product x * y without modifying the operands.
+	Multiply(x, y *big.Int) *big.Int
+	// Name returns a short human-readable name of the algorithm.
+	Name() string
 }
 
-// karatsuba - recursive function implementing the Karatsuba algorithm for big.Int values.
-func karatsuba(x, y *big.Int) *big.Int {
-	// Set a threshold for switching to simple multiplication to avoid deep recursion.
-	const threshold = 10
+// SchoolbookMultiplier - struct implementing the quadratic grade-school multiplication on machine words.
+type SchoolbookMultiplier struct{}
+
+// Multiply - method multiplying two big.Int values word by word.
+func (SchoolbookMultiplier) Multiply(x, y *big.Int) *big.Int {
+	return schoolbookMultiply(x, y)
+}
 
-	// Base case: if either number has fewer than "threshold" bits, use simple multiplication.
-	if x.BitLen() < threshold || y.BitLen() < threshold {
-		result := new(big.Int)
-		return result.Mul(x, y)
+// Name - method returning the name of the algorithm.
+func (SchoolbookMultiplier) Name() string {
+	return "schoolbook"
+}
+
+// MathBigMultiplier - struct wrapping big.Int.Mul so the standard library can be benchmarked as a Multiplier.
+type MathBigMultiplier struct{}
+
+// Multiply - method delegating to big.Int.Mul.
+func (MathBigMultiplier) Multiply(x, y *big.Int) *big.Int {
+	return new(big.Int).Mul(x, y)
+}
+
+// Name - method returning the name of the algorithm.
+func (MathBigMultiplier) Name() string {
+	return "math/big"
+}
+
+// KaratsubaMultiplier - struct containing methods to perform multiplication using the Karatsuba algorithm.
+// Cutoff is the operand size in bits below which schoolbook multiplication is used; zero selects the tuned default,
+// and positive values below 2 are raised to 2.
+type KaratsubaMultiplier struct {
+	Cutoff int
+}
+
+// KaratsubaMultiply - method to initiate multiplication using the Karatsuba algorithm for big.Int values.
+func (km KaratsubaMultiplier) KaratsubaMultiply(x, y *big.Int) *big.Int {
+	return km.Multiply(x, y)
+}
+
+// Multiply - method implementing the Multiplier interface with the Karatsuba algorithm.
+func (km KaratsubaMultiplier) Multiply(x, y *big.Int) *big.Int {
+	cutoff := km.Cutoff
+	if cutoff <= 0 {
+		cutoff = defaultKaratsubaCutoff
+	}
+	cutoff = max(cutoff, minKaratsubaCutoff)
+	result := karatsuba(new(big.Int).Abs(x), new(big.Int).Abs(y), cutoff)
+	if x.Sign()*y.Sign() < 0 {
+		result.Neg(result)
 	}
+	return result
+}
+
+// Name - method returning the name of the algorithm.
+func (KaratsubaMultiplier) Name() string {
+	return "karatsuba"
+}
 
-	// Determine the number of digits in the larger number.
+// karatsuba - recursive function implementing the Karatsuba algorithm for non-negative big.Int values.
+func karatsuba(x, y *big.Int, cutoff int) *big.Int {
+	// Base case: if either number has fewer than "cutoff" bits, use schoolbook multiplication.
+	if x.BitLen() < cutoff || y.BitLen() < cutoff {
+		return schoolbookMultiply(x, y)
+	}
+
+	// Determine the number of bits in the larger number.
 	n := x.BitLen()
 	if y.BitLen() > n {
 		n = y.BitLen()
 	}
-	m := n / 2
+	m := uint(n / 2)
 
-	// Calculate the power of ten once to reduce redundant calculations.
-	powerOfTenM := new(big.Int).Lsh(big.NewInt(1), uint(m))
-
-	// Split x and y into high and low parts.
-	highX := new(big.Int).Div(x, powerOfTenM)
-	lowX := new(big.Int).Mod(x, powerOfTenM)
-	highY := new(big.Int).Div(y, powerOfTenM)
-	lowY := new(big.Int).Mod(y, powerOfTenM)
+	// Split x and y into high and low parts around 2^m.
+	highX, lowX := splitAt(x, m)
+	highY, lowY := splitAt(y, m)
 
 	// Recursively calculate three products.
-	z0 := karatsuba(lowX, lowY)
-	z2 := karatsuba(highX, highY)
+	z0 := karatsuba(lowX, lowY, cutoff)
+	z2 := karatsuba(highX, highY, cutoff)
 
 	// Calculate (lowX + highX) * (lowY + highY) and store the result in z1.
 	sumX := new(big.Int).Add(lowX, highX)
 	sumY := new(big.Int).Add(lowY, highY)
-	z1 := karatsuba(sumX, sumY)
+	z1 := karatsuba(sumX, sumY, cutoff)
 
 	// Combine the results to get the final product.
-	// result = (z2 * powerOfTenM^2) + ((z1 - z2 - z0) * powerOfTenM) + z0
-	result := new(big.Int).Mul(z2, new(big.Int).Mul(powerOfTenM, powerOfTenM))
+	// result = (z2 * 2^(2m)) + ((z1 - z2 - z0) * 2^m) + z0
 	z1.Sub(z1, z2).Sub(z1, z0)
-	result.Add(result, new(big.Int).Mul(z1, powerOfTenM)).Add(result, z0)
+	result := new(big.Int).Lsh(z2, 2*m)
+	result.Add(result, z1.Lsh(z1, m)).Add(result, z0)
 
 	return result
 }
 
+// ToomCook3Multiplier - struct performing multiplication with the Toom-Cook-3 algorithm.
+// Each operand is split into three parts, so five recursive products replace the nine of the schoolbook scheme.
+// Cutoff works as for KaratsubaMultiplier, except that positive values below 4 are raised to 4.
+type ToomCook3Multiplier struct {
+	Cutoff int
+}
+
+// Multiply - method implementing the Multiplier interface with the Toom-Cook-3 algorithm.
+func (tm ToomCook3Multiplier) Multiply(x, y *big.Int) *big.Int {
+	cutoff := tm.Cutoff
+	if cutoff <= 0 {
+		cutoff = defaultToomCook3Cutoff
+	}
+	cutoff = max(cutoff, minToomCook3Cutoff)
+	return toomCook3(x, y, cutoff)
+}
+
+// Name - method returning the name of the algorithm.
+func (ToomCook3Multiplier) Name() string {
+	return "toom-cook-3"
+}
+
+// toomCook3 - recursive function implementing Toom-Cook-3 with evaluation points 0, 1, -1, -2 and infinity.
+func toomCook3(x, y *big.Int, cutoff int) *big.Int {
+	// Base case: small operands are multiplied directly.
+	if x.BitLen() < cutoff || y.BitLen() < cutoff {
+		return schoolbookMultiply(x, y)
+	}
+
+	// Evaluation at -1 and -2 produces negative values, so the recursion works on absolute values.
+	negative := x.Sign()*y.Sign() < 0
+	x = new(big.Int).Abs(x)
+	y = new(big.Int).Abs(y)
+
+	// Determine the size of one part: a third of the larger operand, rounded up.
+	n := x.BitLen()
+	if y.BitLen() > n {
+		n = y.BitLen()
+	}
+	k := uint((n + 2) / 3)
+
+	// Split x = x2*B^2 + x1*B + x0 and y likewise, where B = 2^k.
+	x2, rest := splitAt(x, 2*k)
+	x1, x0 := splitAt(rest, k)
+	y2, rest := splitAt(y, 2*k)
+	y1, y0 := splitAt(rest, k)
+
+	// Evaluate both polynomials at 0, 1, -1, -2 and infinity.
+	px0, px1, pxm1, pxm2, pxInf := evaluateToom3(x0, x1, x2)
+	py0, py1, pym1, pym2, pyInf := evaluateToom3(y0, y1, y2)
+
+	// Five pointwise products.
+	r0 := toomCook3(px0, py0, cutoff)
+	r1 := toomCook3(px1, py1, cutoff)
+	rm1 := toomCook3(pxm1, pym1, cutoff)
+	rm2 := toomCook3(pxm2, pym2, cutoff)
+	rInf := toomCook3(pxInf, pyInf, cutoff)
+
+	// Interpolate the product coefficients using Bodrato's sequence; all divisions are exact.
+	c0 := r0
+	c4 := rInf
+	c3 := new(big.Int).Sub(rm2, r1)
+	c3.Quo(c3, big.NewInt(3))
+	c1 := new(big.Int).Sub(r1, rm1)
+	c1.Rsh(c1, 1)
+	c2 := new(big.Int).Sub(rm1, r0)
+	c3.Sub(c2, c3).Quo(c3, big.NewInt(2))
+	c3.Add(c3, new(big.Int).Lsh(rInf, 1))
+	c2.Add(c2, c1).Sub(c2, c4)
+	c1.Sub(c1, c3)
+
+	// Recompose: result = c4*B^4 + c3*B^3 + c2*B^2 + c1*B + c0.
+	result := new(big.Int).Lsh(c4, 4*k)
+	result.Add(result, c3.Lsh(c3, 3*k))
+	result.Add(result, c2.Lsh(c2, 2*k))
+	result.Add(result, c1.Lsh(c1, k))
+	result.Add(result, c0)
+
+	if negative {
+		result.Neg(result)
+	}
+	return result
+}
+
+// evaluateToom3 - function evaluating a0 + a1*t + a2*t^2 at t = 0, 1, -1, -2 and infinity.
+func evaluateToom3(a0, a1, a2 *big.Int) (p0, p1, pm1, pm2, pInf *big.Int) {
+	sum := new(big.Int).Add(a0, a2)
+	p0 = a0
+	p1 = new(big.Int).Add(sum, a1)
+	pm1 = new(big.Int).Sub(sum, a1)
+	// p(-2) = 2*(p(-1) + a2) - a0.
+	pm2 = new(big.Int).Add(pm1, a2)
+	pm2.Lsh(pm2, 1).Sub(pm2, a0)
+	pInf = a2
+	return p0, p1, pm1, pm2, pInf
+}
+
+// Parameters of the two NTT-friendly primes; both have 3 as a primitive root.
+// Their product exceeds 2^58, which bounds every convolution coefficient of 16-bit limbs up to maxNTTLength.
+const (
+	nttPrime1    = 998244353 // 119 * 2^23 + 1
+	nttPrime2    = 469762049 // 7 * 2^26 + 1
+	nttRoot      = 3
+	nttLimbBits  = 16
+	maxNTTLength = 1 << 23
+)
+
+// NTTMultiplier - struct performing multiplication with the number-theoretic transform.
+// Operands are cut into 16-bit limbs, convolved modulo two primes and recombined with the Chinese remainder theorem.
+type NTTMultiplier struct {
+	Cutoff int
+}
+
+// Multiply - method implementing the Multiplier interface with the number-theoretic transform.
+func (nm NTTMultiplier) Multiply(x, y *big.Int) *big.Int {
+	cutoff := nm.Cutoff
+	if cutoff <= 0 {
+		cutoff = defaultNTTCutoff
+	}
+	if x.BitLen() < cutoff || y.BitLen() < cutoff {
+		return schoolbookMultiply(x, y)
+	}
+
+	xLimbs := toLimbs(x)
+	yLimbs := toLimbs(y)
+	size := 1
+	for size < len(xLimbs)+len(yLimbs) {
+		size <<= 1
+	}
+	// Beyond the transform length supported by the first prime, split the work with Toom-Cook-3.
+	if size > maxNTTLength {
+		return toomCook3(x, y, cutoff)
+	}
+
+	// Convolve the limbs modulo each prime.
+	residues1 := convolveMod(xLimbs, yLimbs, size, newNTTModulus(nttPrime1))
+	residues2 := convolveMod(xLimbs, yLimbs, size, newNTTModulus(nttPrime2))
+
+	// Recombine each coefficient with the CRT and propagate carries into 16-bit limbs.
+	inverse := powMod(nttPrime1%nttPrime2, nttPrime2-2, nttPrime2)
+	limbs := make([]uint64, size+4)
+	var carry uint64
+	for i := 0; i < size; i++ {
+		r1, r2 := residues1[i], residues2[i]
+		t := (r2 + nttPrime2 - r1%nttPrime2) % nttPrime2 * inverse % nttPrime2
+		value := r1 + nttPrime1*t + carry
+		limbs[i] = value & (1<<nttLimbBits - 1)
+		carry = value >> nttLimbBits
+	}
+	for i := size; carry > 0; i++ {
+		limbs[i] = carry & (1<<nttLimbBits - 1)
+		carry >>= nttLimbBits
+	}
+
+	result := fromLimbs(limbs)
+	if x.Sign()*y.Sign() < 0 {
+		result.Neg(result)
+	}
+	return result
+}
+
+// Name - method returning the name of the algorithm.
+func (NTTMultiplier) Name() string {
+	return "ntt"
+}
+
+// nttModulus - struct holding an NTT prime together with its Barrett constant floor(2^62 / prime),
+// so modular products inside the transform avoid hardware division.
+type nttModulus struct {
+	prime   uint64
+	barrett uint64
+}
+
+// newNTTModulus - constructor precomputing the Barrett constant of the given prime.
+func newNTTModulus(prime uint64) nttModulus {
+	return nttModulus{prime: prime, barrett: (1 << 62) / prime}
+}
+
+// mul - method returning a * b modulo the prime for a, b < prime < 2^30.
+func (m nttModulus) mul(a, b uint64) uint64 {
+	product := a * b
+	hi, lo := bits.Mul64(product, m.barrett)
+	// The estimated quotient is at most two below the true one.
+	remainder := product - (hi<<2|lo>>62)*m.prime
+	for remainder >= m.prime {
+		remainder -= m.prime
+	}
+	return remainder
+}
+
+// convolveMod - function computing the cyclic convolution of a and b of the given size modulo a prime.
+func convolveMod(a, b []uint64, size int, mod nttModulus) []uint64 {
+	fa := make([]uint64, size)
+	fb := make([]uint64, size)
+	copy(fa, a)
+	copy(fb, b)
+	ntt(fa, false, mod)
+	ntt(fb, false, mod)
+	for i := range fa {
+		fa[i] = mod.mul(fa[i], fb[i])
+	}
+	ntt(fa, true, mod)
+	return fa
+}
+
+// ntt - function performing an in-place iterative radix-2 number-theoretic transform modulo a prime.
+func ntt(a []uint64, invert bool, mod nttModulus) {
+	n := len(a)
+	prime := mod.prime
+
+	// Reorder the elements into bit-reversed order.
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+
+	// Butterfly passes of doubling length, with the twiddle factors of each pass precomputed.
+	twiddles := make([]uint64, n/2)
+	for length := 2; length <= n; length <<= 1 {
+		w := powMod(nttRoot, (prime-1)/uint64(length), prime)
+		if invert {
+			w = powMod(w, prime-2, prime)
+		}
+		half := length / 2
+		twiddles[0] = 1
+		for j := 1; j < half; j++ {
+			twiddles[j] = mod.mul(twiddles[j-1], w)
+		}
+		for start := 0; start < n; start += length {
+			for j := 0; j < half; j++ {
+				u := a[start+j]
+				v := mod.mul(a[start+j+half], twiddles[j])
+				if a[start+j] = u + v; a[start+j] >= prime {
+					a[start+j] -= prime
+				}
+				if u < v {
+					u += prime
+				}
+				a[start+j+half] = u - v
+			}
+		}
+	}
+
+	// Scale by n^-1 for the inverse transform.
+	if invert {
+		nInverse := powMod(uint64(n), prime-2, prime)
+		for i := range a {
+			a[i] = mod.mul(a[i], nInverse)
+		}
+	}
+}
+
+// powMod - function computing base^exponent modulo mod by repeated squaring.
+func powMod(base, exponent, mod uint64) uint64 {
+	result := uint64(1)
+	base %= mod
+	for exponent > 0 {
+		if exponent&1 == 1 {
+			result = result * base % mod
+		}
+		base = base * base % mod
+		exponent >>= 1
+	}
+	return result
+}
+
+// toLimbs - function cutting the absolute value of x into little-endian 16-bit limbs.
+func toLimbs(x *big.Int) []uint64 {
+	words := x.Bits()
+	perWord := bits.UintSize / nttLimbBits
+	limbs := make([]uint64, 0, len(words)*perWord)
+	for _, word := range words {
+		for i := 0; i < perWord; i++ {
+			limbs = append(limbs, uint64(word>>(i*nttLimbBits))&(1<<nttLimbBits-1))
+		}
+	}
+	// Drop the leading zero limbs so the transform is no longer than necessary.
+	for len(limbs) > 0 && limbs[len(limbs)-1] == 0 {
+		limbs = limbs[:len(limbs)-1]
+	}
+	return limbs
+}
+
+// fromLimbs - function assembling a non-negative big.Int from little-endian 16-bit limbs.
+func fromLimbs(limbs []uint64) *big.Int {
+	perWord := bits.UintSize / nttLimbBits
+	words := make([]big.Word, (len(limbs)+perWord-1)/perWord)
+	for i, limb := range limbs {
+		words[i/perWord] |= big.Word(limb) << ((i % perWord) * nttLimbBits)
+	}
+	return new(big.Int).SetBits(words)
+}
+
+// splitAt - function splitting a non-negative x into x >> m and x mod 2^m.
+func splitAt(x *big.Int, m uint) (high, low *big.Int) {
+	high = new(big.Int).Rsh(x, m)
+	low = new(big.Int).Sub(x, new(big.Int).Lsh(high, m))
+	return high, low
+}
+
+// schoolbookMultiply - function multiplying two big.Int values with the quadratic word-by-word method.
+func schoolbookMultiply(x, y *big.Int) *big.Int {
+	xWords, yWords := x.Bits(), y.Bits()
+	if len(xWords) == 0 || len(yWords) == 0 {
+		return new(big.Int)
+	}
+
+	product := make([]big.Word, len(xWords)+len(yWords))
+	for i, a := range xWords {
+		var carry uint
+		for j, b := range yWords {
+			// product[i+j] + a*b + carry never exceeds two words.
+			hi, lo := bits.Mul(uint(a), uint(b))
+			var c uint
+			lo, c = bits.Add(lo, uint(product[i+j]), 0)
+			hi += c
+			lo, c = bits.Add(lo, carry, 0)
+			hi += c
+			product[i+j] = big.Word(lo)
+			carry = hi
+		}
+		product[i+len(yWords)] = big.Word(carry)
+	}
+
+	result := new(big.Int).SetBits(product)
+	if x.Sign()*y.Sign() < 0 {
+		result.Neg(result)
+	}
+	return result
+}
+
+// randomDigits - function generating a random positive integer with exactly the given number of decimal digits.
+func randomDigits(rng *rand.Rand, digits int) *big.Int {
+	var sb strings.Builder
+	sb.WriteByte(byte('1' + rng.Intn(9)))
+	for i := 1; i < digits; i++ {
+		sb.WriteByte(byte('0' + rng.Intn(10)))
+	}
+	result, _ := new(big.Int).SetString(sb.String(), 10)
+	return result
+}
+
+// runBenchmarks - function timing every multiplier on operands of growing size and reporting
+// the first size at which each algorithm is at least as fast as math/big.
+func runBenchmarks(multipliers []Multiplier, maxDigits int) {
+	rng := rand.New(rand.NewSource(1))
+	baseline := MathBigMultiplier{}
+	crossovers := make(map[string]int)
+
+	fmt.Printf("%10s", "digits")
+	for _, m := range multipliers {
+		fmt.Printf(" %14s", m.Name())
+	}
+	fmt.Printf(" %14s\n", baseline.Name())
+
+	for digits := 1000; digits <= maxDigits; digits *= 2 {
+		x := randomDigits(rng, digits)
+		y := randomDigits(rng, digits)
+
+		timeOf := func(m Multiplier) int64 {
+			return testing.Benchmark(func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					m.Multiply(x, y)
+				}
+			}).NsPerOp()
+		}
+
+		baselineTime := timeOf(baseline)
+		fmt.Printf("%10d", digits)
+		for _, m := range multipliers {
+			elapsed := timeOf(m)
+			fmt.Printf(" %12dns", elapsed)
+			if _, found := crossovers[m.Name()]; !found && elapsed <= baselineTime {
+				crossovers[m.Name()] = digits
+			}
+		}
+		fmt.Printf(" %12dns\n", baselineTime)
+	}
+
+	fmt.Println("Crossover points against math/big:")
+	for _, m := range multipliers {
+		if digits, found := crossovers[m.Name()]; found {
+			fmt.Printf("  %-12s faster from %d digits\n", m.Name(), digits)
+		} else {
+			fmt.Printf("  %-12s not reached up to %d digits\n", m.Name(), maxDigits)
+		}
+	}
+}
+
 func main() {
+	bench := flag.Bool("bench", false, "benchmark all multipliers against math/big")
+	maxDigits := flag.Int("max-digits", 256000, "largest operand size in decimal digits for the benchmark")
+	flag.Parse()
+
 	// Define large integers as strings and parse them into big.Int.
 	x := new(big.Int)
 	y := new(big.Int)
@@ -69,4 +536,31 @@ func main() {
 	result := KaratsubaMultiplier{}.KaratsubaMultiply(x, y)
 
 	fmt.Println("Product:", result)
+
+	// Cross-check every multiplier against math/big with small cutoffs so the recursions are exercised;
+	// cutoffs below the minimum are raised to it instead of recursing forever.
+	multipliers := []Multiplier{
+		SchoolbookMultiplier{},
+		KaratsubaMultiplier{Cutoff: 64},
+		ToomCook3Multiplier{Cutoff: 64},
+		KaratsubaMultiplier{Cutoff: 1},
+		ToomCook3Multiplier{Cutoff: 2},
+		NTTMultiplier{Cutoff: 64},
+	}
+	rng := rand.New(rand.NewSource(42))
+	a := randomDigits(rng, 5000)
+	b := new(big.Int).Neg(randomDigits(rng, 3000))
+	expected := new(big.Int).Mul(a, b)
+	for _, m := range multipliers {
+		fmt.Printf("%-12s matches math/big: %v\n", m.Name(), m.Multiply(a, b).Cmp(expected) == 0)
+	}
+
+	if *bench {
+		runBenchmarks([]Multiplier{
+			SchoolbookMultiplier{},
+			KaratsubaMultiplier{},
+			ToomCook3Multiplier{},
+			NTTMultiplier{},
+		}, *maxDigits)
+	}
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"container/heap"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math"
@@ -9,87 +11,185 @@ import (
 	"time"
 )
 
-type Point struct {
-	X, Y float64
+// Point is a point in any number of dimensions; Point{x, y} is a point in the plane.
+type Point []float64
+
+// Metric measures the distance between two points of the same dimension.
+// Every metric used by the solver must satisfy d(p, q) >= |p[i] - q[i]| for each coordinate i,
+// which holds for all Lp norms and is what the strip pruning relies on.
+type Metric func(p1, p2 Point) float64
+
+// PointPair is a pair of points together with their distance.
+type PointPair struct {
+	Distance float64
+	Pair     [2]Point
 }
 
 // ClosestPairSolver initializes the solver with the list of points.
 type ClosestPairSolver struct {
 	points []Point
+	metric Metric
 }
 
+// NewClosestPairSolver creates a solver that measures distances with EuclideanDistance.
 func NewClosestPairSolver(points []Point) *ClosestPairSolver {
-	return &ClosestPairSolver{points: points}
+	return &ClosestPairSolver{points: points, metric: EuclideanDistance}
 }
 
-// EuclideanDistance calculates the Euclidean distance between two points.
+// NewClosestPairSolverWithMetric creates a solver that measures distances with the given metric.
+func NewClosestPairSolverWithMetric(points []Point, metric Metric) *ClosestPairSolver {
+	return &ClosestPairSolver{points: points, metric: metric}
+}
+
+// EuclideanDistance calculates the Euclidean (L2) distance between two points.
 func EuclideanDistance(p1, p2 Point) float64 {
-	return math.Sqrt((p1.X-p2.X)*(p1.X-p2.X) + (p1.Y-p2.Y)*(p1.Y-p2.Y))
+	sum := 0.0
+	for i := range p1 {
+		sum += (p1[i] - p2[i]) * (p1[i] - p2[i])
+	}
+	return math.Sqrt(sum)
+}
+
+// ManhattanDistance calculates the Manhattan (L1) distance between two points.
+func ManhattanDistance(p1, p2 Point) float64 {
+	sum := 0.0
+	for i := range p1 {
+		sum += math.Abs(p1[i] - p2[i])
+	}
+	return sum
+}
+
+// ChebyshevDistance calculates the Chebyshev (L-infinity) distance between two points.
+func ChebyshevDistance(p1, p2 Point) float64 {
+	maxDiff := 0.0
+	for i := range p1 {
+		maxDiff = math.Max(maxDiff, math.Abs(p1[i]-p2[i]))
+	}
+	return maxDiff
+}
+
+// pairHeap is a max-heap of candidate pairs, so the worst of the k best pairs sits on top.
+type pairHeap []candidatePair
+
+// candidatePair is a pair of point indices together with their distance.
+type candidatePair struct {
+	distance float64
+	i, j     int
+}
+
+func (h pairHeap) Len() int           { return len(h) }
+func (h pairHeap) Less(i, j int) bool { return h[i].distance > h[j].distance }
+func (h pairHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *pairHeap) Push(x any)        { *h = append(*h, x.(candidatePair)) }
+func (h *pairHeap) Pop() any {
+	old := *h
+	pair := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return pair
+}
+
+// pairCollector keeps the k closest pairs seen so far.
+type pairCollector struct {
+	k     int
+	pairs pairHeap
+}
+
+// offer records the pair (i, j) if it is among the k closest seen so far.
+func (c *pairCollector) offer(distance float64, i, j int) {
+	if len(c.pairs) < c.k {
+		heap.Push(&c.pairs, candidatePair{distance: distance, i: i, j: j})
+	} else if distance < c.pairs[0].distance {
+		c.pairs[0] = candidatePair{distance: distance, i: i, j: j}
+		heap.Fix(&c.pairs, 0)
+	}
+}
+
+// delta returns the distance a new pair must beat: the k-th best distance, or infinity until k pairs are known.
+func (c *pairCollector) delta() float64 {
+	if len(c.pairs) < c.k {
+		return math.Inf(1)
+	}
+	return c.pairs[0].distance
 }
 
 // BruteForce finds the closest pair using brute-force search.
 func (solver *ClosestPairSolver) BruteForce(points []Point) (float64, [2]Point) {
-	minDist := math.Inf(1)
-	var closestPair [2]Point
+	pairs := solver.BruteForceK(points, 1)
+	if len(pairs) == 0 {
+		return math.Inf(1), [2]Point{}
+	}
+	return pairs[0].Distance, pairs[0].Pair
+}
+
+// BruteForceK finds the k closest pairs by comparing every pair of points.
+func (solver *ClosestPairSolver) BruteForceK(points []Point, k int) []PointPair {
+	collector := &pairCollector{k: k}
 	n := len(points)
 
 	for i := 0; i < n; i++ {
 		for j := i + 1; j < n; j++ {
-			dist := EuclideanDistance(points[i], points[j])
-			if dist < minDist {
-				minDist = dist
-				closestPair = [2]Point{points[i], points[j]}
-			}
+			collector.offer(solver.metric(points[i], points[j]), i, j)
 		}
 	}
-	return minDist, closestPair
+	return collector.sortedPairs(points)
 }
 
-// ClosestSplitPair checks for the closest pair across the divide.
-func (solver *ClosestPairSolver) ClosestSplitPair(px, py []Point, delta float64, bestPair [2]Point) (float64, [2]Point) {
-	midX := px[len(px)/2].X
-	var sy []Point
+// ClosestSplitPair checks for the closest pairs across the divide.
+// px and py hold the indices of the current subproblem sorted by the first and second coordinate,
+// and rankX gives each index's position in the global first-coordinate order, which tells the sides apart.
+func (solver *ClosestPairSolver) ClosestSplitPair(px, py []int, rankX []int, best *pairCollector) {
+	mid := len(px) / 2
+	midRank := rankX[px[mid]]
+	midX := solver.points[px[mid]][0]
+	var sy []int
 
-	// Collect points within the delta-width vertical strip.
+	// Collect points within the delta-width strip around the dividing coordinate.
+	delta := best.delta()
 	for _, p := range py {
-		if math.Abs(p.X-midX) < delta {
+		if math.Abs(solver.points[p][0]-midX) < delta {
 			sy = append(sy, p)
 		}
 	}
 
-	bestDist := delta
-	lenSy := len(sy)
-
-	// Compare up to 7 closest neighbors.
-	for i := 0; i < lenSy; i++ {
-		for j := 1; j < 8 && i+j < lenSy; j++ {
-			p, q := sy[i], sy[i+j]
-			dist := EuclideanDistance(p, q)
-			if dist < bestDist {
-				bestDist = dist
-				bestPair = [2]Point{p, q}
+	// Compare each point with the following strip points until their second coordinates differ by delta.
+	// Only pairs with one point on each side are new; the others were handled by the recursion.
+	axis := secondAxis(solver.points[px[0]])
+	for i := 0; i < len(sy); i++ {
+		p := solver.points[sy[i]]
+		for j := i + 1; j < len(sy); j++ {
+			q := solver.points[sy[j]]
+			if q[axis]-p[axis] >= best.delta() {
+				break
+			}
+			if (rankX[sy[i]] < midRank) != (rankX[sy[j]] < midRank) {
+				best.offer(solver.metric(p, q), sy[i], sy[j])
 			}
 		}
 	}
-	return bestDist, bestPair
 }
 
 // ClosestPairRecursive is a recursive divide-and-conquer method.
-func (solver *ClosestPairSolver) ClosestPairRecursive(px, py []Point) (float64, [2]Point) {
+func (solver *ClosestPairSolver) ClosestPairRecursive(px, py []int, rankX []int, best *pairCollector) {
 	n := len(px)
 	if n <= 3 {
-		return solver.BruteForce(px)
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				best.offer(solver.metric(solver.points[px[i]], solver.points[px[j]]), px[i], px[j])
+			}
+		}
+		return
 	}
 
 	mid := n / 2
 	Qx := px[:mid]
 	Rx := px[mid:]
 
-	// Split py into Qy and Ry.
-	var Qy, Ry []Point
-	midpointX := px[mid].X
+	// Split py into Qy and Ry by rank, which stays correct when first coordinates repeat.
+	midRank := rankX[px[mid]]
+	Qy := make([]int, 0, mid)
+	Ry := make([]int, 0, n-mid)
 	for _, p := range py {
-		if p.X <= midpointX {
+		if rankX[p] < midRank {
 			Qy = append(Qy, p)
 		} else {
 			Ry = append(Ry, p)
@@ -97,53 +197,336 @@ func (solver *ClosestPairSolver) ClosestPairRecursive(px, py []Point) (float64,
 	}
 
 	// Recursive calls for left and right halves.
-	distLeft, pairLeft := solver.ClosestPairRecursive(Qx, Qy)
-	distRight, pairRight := solver.ClosestPairRecursive(Rx, Ry)
-
-	// Choose the minimum distance from two halves.
-	var delta float64
-	var bestPair [2]Point
-	if distLeft < distRight {
-		delta = distLeft
-		bestPair = pairLeft
-	} else {
-		delta = distRight
-		bestPair = pairRight
-	}
+	solver.ClosestPairRecursive(Qx, Qy, rankX, best)
+	solver.ClosestPairRecursive(Rx, Ry, rankX, best)
 
-	// Check for closest split pair.
-	distSplit, pairSplit := solver.ClosestSplitPair(px, py, delta, bestPair)
-
-	// Return the smallest of the distances found.
-	if delta < distSplit {
-		return delta, bestPair
-	} else {
-		return distSplit, pairSplit
-	}
+	// Check for closest split pairs.
+	solver.ClosestSplitPair(px, py, rankX, best)
 }
 
 // FindClosestPair initializes and runs the closest-pair search.
 func (solver *ClosestPairSolver) FindClosestPair() (float64, [2]Point, error) {
-	if len(solver.points) < 2 {
-		return 0, [2]Point{}, errors.New("at least two points are required to find the closest pair")
+	pairs, err := solver.FindKClosestPairs(1)
+	if err != nil {
+		return 0, [2]Point{}, err
 	}
+	return pairs[0].Distance, pairs[0].Pair, nil
+}
 
-	// Sort points by X and Y coordinates.
-	px := make([]Point, len(solver.points))
-	py := make([]Point, len(solver.points))
-	copy(px, solver.points)
-	copy(py, solver.points)
+// FindKClosestPairs returns the k closest pairs in increasing order of distance.
+// When fewer than k pairs exist, all pairs are returned.
+func (solver *ClosestPairSolver) FindKClosestPairs(k int) ([]PointPair, error) {
+	if len(solver.points) < 2 {
+		return nil, errors.New("at least two points are required to find the closest pair")
+	}
+	if k < 1 {
+		return nil, errors.New("k must be positive")
+	}
+	if err := validateDimensions(solver.points); err != nil {
+		return nil, err
+	}
 
+	// Sort point indices by the first and by the second coordinate.
+	n := len(solver.points)
+	px := make([]int, n)
+	py := make([]int, n)
+	for i := range px {
+		px[i] = i
+		py[i] = i
+	}
+	axis := secondAxis(solver.points[0])
 	sort.Slice(px, func(i, j int) bool {
-		return px[i].X < px[j].X
+		return solver.points[px[i]][0] < solver.points[px[j]][0]
 	})
 	sort.Slice(py, func(i, j int) bool {
-		return py[i].Y < py[j].Y
+		return solver.points[py[i]][axis] < solver.points[py[j]][axis]
 	})
+	rankX := make([]int, n)
+	for rank, p := range px {
+		rankX[p] = rank
+	}
 
 	// Run recursive algorithm.
-	dist, pair := solver.ClosestPairRecursive(px, py)
-	return dist, pair, nil
+	best := &pairCollector{k: k}
+	solver.ClosestPairRecursive(px, py, rankX, best)
+	return best.sortedPairs(solver.points), nil
+}
+
+// sortedPairs returns the collected pairs in increasing order of distance.
+func (c *pairCollector) sortedPairs(points []Point) []PointPair {
+	result := make([]PointPair, len(c.pairs))
+	for i, pair := range c.pairs {
+		result[i] = PointPair{Distance: pair.distance, Pair: [2]Point{points[pair.i], points[pair.j]}}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Distance < result[j].Distance
+	})
+	return result
+}
+
+// secondAxis returns the coordinate the strip is scanned along: the second one, or the first for 1-D points.
+func secondAxis(p Point) int {
+	if len(p) > 1 {
+		return 1
+	}
+	return 0
+}
+
+// validateDimensions checks that all points have the same, non-zero number of coordinates.
+func validateDimensions(points []Point) error {
+	dimension := len(points[0])
+	if dimension == 0 {
+		return errors.New("points must have at least one coordinate")
+	}
+	for i, p := range points {
+		if len(p) != dimension {
+			return fmt.Errorf("point %d has %d coordinates, expected %d", i, len(p), dimension)
+		}
+	}
+	return nil
+}
+
+// Color labels a point for the bichromatic (red-blue) closest pair query.
+type Color int
+
+const (
+	Red Color = iota
+	Blue
+)
+
+// DynamicClosestPair maintains the closest pair of a growing point set with randomized grid hashing
+// in the style of Rabin and Golin et al. Points are bucketed into a grid whose cell side equals the
+// current closest distance, so an insertion only inspects the 3^d surrounding cells. The grid is
+// rebuilt whenever the distance shrinks; when points arrive in random order, the i-th insertion
+// changes the answer with probability at most 2/i, which makes insertion expected O(1).
+// The same structure is kept separately for red-blue pairs to answer bichromatic queries. That bound
+// does not carry over to them: same-colored points are never compared, so nothing limits how many
+// points of one color share a cell, and an insertion costs time proportional to the opposite-colored
+// points around it. A dense cluster of one color makes bichromatic insertion O(n) in the worst case.
+type DynamicClosestPair struct {
+	metric      Metric
+	points      []Point
+	colors      []Color
+	all         *gridIndex
+	bichromatic *gridIndex
+}
+
+// NewDynamicClosestPair creates an empty structure that measures distances with the given metric.
+// The metric must satisfy the same coordinate bound as for ClosestPairSolver.
+func NewDynamicClosestPair(metric Metric) *DynamicClosestPair {
+	dc := &DynamicClosestPair{metric: metric}
+	dc.all = newGridIndex(dc, func(Color, Color) bool { return true })
+	dc.bichromatic = newGridIndex(dc, func(a, b Color) bool { return a != b })
+	return dc
+}
+
+// Insert adds a colored point and updates both the overall and the red-blue closest pair.
+func (dc *DynamicClosestPair) Insert(p Point, color Color) error {
+	if len(p) == 0 {
+		return errors.New("points must have at least one coordinate")
+	}
+	if len(dc.points) > 0 && len(p) != len(dc.points[0]) {
+		return fmt.Errorf("point has %d coordinates, expected %d", len(p), len(dc.points[0]))
+	}
+	dc.points = append(dc.points, p)
+	dc.colors = append(dc.colors, color)
+	index := len(dc.points) - 1
+	dc.all.insert(index)
+	dc.bichromatic.insert(index)
+	return nil
+}
+
+// Len returns the number of inserted points.
+func (dc *DynamicClosestPair) Len() int {
+	return len(dc.points)
+}
+
+// ClosestPair returns the closest pair of points regardless of color; ok is false with fewer than two points.
+func (dc *DynamicClosestPair) ClosestPair() (pair PointPair, ok bool) {
+	return dc.all.result()
+}
+
+// ClosestBichromaticPair returns the closest pair made of one red and one blue point;
+// ok is false until both colors are present.
+func (dc *DynamicClosestPair) ClosestBichromaticPair() (pair PointPair, ok bool) {
+	return dc.bichromatic.result()
+}
+
+// gridIndex is a hash grid over the inserted points that tracks the closest eligible pair.
+// Each cell keeps one bucket per color so that a query only visits points it may pair with.
+type gridIndex struct {
+	owner    *DynamicClosestPair
+	eligible func(a, b Color) bool
+	best     candidatePair
+	found    bool
+	cells    map[string]map[Color][]int
+	// byColor lists the points inserted so far per color, used until the first eligible pair exists.
+	byColor map[Color][]int
+}
+
+// newGridIndex creates an empty grid for pairs accepted by eligible.
+func newGridIndex(owner *DynamicClosestPair, eligible func(a, b Color) bool) *gridIndex {
+	return &gridIndex{owner: owner, eligible: eligible, byColor: make(map[Color][]int)}
+}
+
+// insert adds the point with the given index and updates the closest eligible pair.
+func (g *gridIndex) insert(index int) {
+	points, colors := g.owner.points, g.owner.colors
+	color := colors[index]
+
+	// No eligible pair yet: compare against every previous point of an eligible color once.
+	if !g.found {
+		for other, members := range g.byColor {
+			if !g.eligible(color, other) {
+				continue
+			}
+			for _, j := range members {
+				distance := g.owner.metric(points[index], points[j])
+				if !g.found || distance < g.best.distance {
+					g.best = candidatePair{distance: distance, i: j, j: index}
+					g.found = true
+				}
+			}
+		}
+		g.byColor[color] = append(g.byColor[color], index)
+		if g.found {
+			g.rebuild()
+		}
+		return
+	}
+	g.byColor[color] = append(g.byColor[color], index)
+
+	// A zero distance can never improve, so the grid is no longer needed.
+	if g.best.distance == 0 {
+		return
+	}
+
+	// Any eligible point closer than the current distance lies in one of the 3^d neighboring cells.
+	improved := false
+	cell := g.cellOf(points[index])
+	g.forEachNeighbor(cell, func(bucket map[Color][]int) {
+		for other, members := range bucket {
+			if !g.eligible(color, other) {
+				continue
+			}
+			for _, j := range members {
+				if distance := g.owner.metric(points[index], points[j]); distance < g.best.distance {
+					g.best = candidatePair{distance: distance, i: j, j: index}
+					improved = true
+				}
+			}
+		}
+	})
+
+	if improved {
+		g.rebuild()
+	} else {
+		g.add(cell, index)
+	}
+}
+
+// rebuild re-buckets every point into a grid whose cell side is the current closest distance.
+func (g *gridIndex) rebuild() {
+	g.cells = make(map[string]map[Color][]int)
+	if g.best.distance == 0 {
+		return
+	}
+	for _, members := range g.byColor {
+		for _, index := range members {
+			g.add(g.cellOf(g.owner.points[index]), index)
+		}
+	}
+}
+
+// add places a point into its cell.
+func (g *gridIndex) add(cell []int64, index int) {
+	key := cellKey(cell)
+	bucket, found := g.cells[key]
+	if !found {
+		bucket = make(map[Color][]int)
+		g.cells[key] = bucket
+	}
+	color := g.owner.colors[index]
+	bucket[color] = append(bucket[color], index)
+}
+
+// maxCellCoordinate bounds grid coordinates so that they fit into an int64.
+const maxCellCoordinate = 1 << 62
+
+// cellOf returns the integer grid coordinates of the cell containing p.
+// A tiny distance can make the quotient overflow an int64, so it is clamped; clamping is monotone,
+// which keeps points closer than the distance in neighboring cells.
+func (g *gridIndex) cellOf(p Point) []int64 {
+	cell := make([]int64, len(p))
+	for i, x := range p {
+		q := math.Floor(x / g.best.distance)
+		cell[i] = int64(math.Max(-maxCellCoordinate, math.Min(maxCellCoordinate, q)))
+	}
+	return cell
+}
+
+// forEachNeighbor calls visit for every non-empty cell within one step of cell in each coordinate.
+func (g *gridIndex) forEachNeighbor(cell []int64, visit func(bucket map[Color][]int)) {
+	neighbor := make([]int64, len(cell))
+	var walk func(d int)
+	walk = func(d int) {
+		if d == len(cell) {
+			if bucket, found := g.cells[cellKey(neighbor)]; found {
+				visit(bucket)
+			}
+			return
+		}
+		for offset := int64(-1); offset <= 1; offset++ {
+			neighbor[d] = cell[d] + offset
+			walk(d + 1)
+		}
+	}
+	walk(0)
+}
+
+// result converts the tracked pair into a PointPair.
+func (g *gridIndex) result() (PointPair, bool) {
+	if !g.found {
+		return PointPair{}, false
+	}
+	points := g.owner.points
+	return PointPair{Distance: g.best.distance, Pair: [2]Point{points[g.best.i], points[g.best.j]}}, true
+}
+
+// cellKey encodes grid coordinates as a map key.
+func cellKey(cell []int64) string {
+	key := make([]byte, 0, len(cell)*binary.MaxVarintLen64)
+	for _, c := range cell {
+		key = binary.AppendVarint(key, c)
+	}
+	return string(key)
+}
+
+// bruteForceBichromatic finds the closest red-blue pair by comparing every pair of differently colored points.
+func bruteForceBichromatic(points []Point, colors []Color, metric Metric) (float64, bool) {
+	best, found := math.Inf(1), false
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			if colors[i] != colors[j] {
+				best = math.Min(best, metric(points[i], points[j]))
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// randomPoints generates n points with the given dimension and coordinates in [-1000, 1000).
+// Coordinates are rounded to integers so that duplicate coordinates and tied distances occur.
+func randomPoints(n, dimension int) []Point {
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = make(Point, dimension)
+		for d := range points[i] {
+			points[i][d] = math.Round((rand.Float64() * 2000) - 1000)
+		}
+	}
+	return points
 }
 
 func main() {
@@ -157,6 +540,16 @@ func main() {
 	}
 	fmt.Printf("The closest pair is %v with a distance of %.2f\n", pair, distance)
 
+	// The three closest pairs of the same points.
+	closestPairs, err := closestPairSolver.FindKClosestPairs(3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	for _, p := range closestPairs {
+		fmt.Printf("  %v at distance %.2f\n", p.Pair, p.Distance)
+	}
+
 	// Edge case: fewer than two points.
 	singlePoint := []Point{{2.1, 3.5}}
 	closestPairSolver = NewClosestPairSolver(singlePoint)
@@ -169,8 +562,8 @@ func main() {
 	var largePoints []Point
 	for i := 0; i < 10000; i++ {
 		largePoints = append(largePoints, Point{
-			X: (rand.Float64() * 2000) - 1000,
-			Y: (rand.Float64() * 2000) - 1000,
+			(rand.Float64() * 2000) - 1000,
+			(rand.Float64() * 2000) - 1000,
 		})
 	}
 	closestPairSolver = NewClosestPairSolver(largePoints)
@@ -180,4 +573,80 @@ func main() {
 		return
 	}
 	fmt.Printf("Closest pair in large dataset: %v with a distance of %.2f\n", pair, distance)
+
+	// Check every metric, dimension and k against brute force by comparing the distance lists.
+	metrics := []struct {
+		name   string
+		metric Metric
+	}{
+		{"Euclidean", EuclideanDistance},
+		{"Manhattan", ManhattanDistance},
+		{"Chebyshev", ChebyshevDistance},
+	}
+	for _, m := range metrics {
+		matches := true
+		for _, dimension := range []int{1, 2, 3, 5} {
+			for _, k := range []int{1, 7, 50} {
+				testPoints := randomPoints(400, dimension)
+				solver := NewClosestPairSolverWithMetric(testPoints, m.metric)
+				fast, err := solver.FindKClosestPairs(k)
+				if err != nil {
+					fmt.Println("Error:", err)
+					return
+				}
+				slow := solver.BruteForceK(testPoints, k)
+				if len(fast) != len(slow) {
+					matches = false
+					continue
+				}
+				for i := range fast {
+					if fast[i].Distance != slow[i].Distance {
+						matches = false
+					}
+				}
+			}
+		}
+		fmt.Printf("%s metric matches brute force in 1, 2, 3 and 5 dimensions: %v\n", m.name, matches)
+	}
+
+	// Insert points one at a time into the grid structure and cross-check it after every insertion:
+	// the overall pair against ClosestPairSolver, the red-blue pair against brute force.
+	for _, m := range metrics {
+		matches := true
+		for _, dimension := range []int{1, 2, 3} {
+			dynamic := NewDynamicClosestPair(m.metric)
+			var inserted []Point
+			var colors []Color
+			for _, p := range randomPoints(300, dimension) {
+				color := Red
+				if rand.Intn(4) == 0 {
+					color = Blue
+				}
+				if err := dynamic.Insert(p, color); err != nil {
+					fmt.Println("Error:", err)
+					return
+				}
+				inserted = append(inserted, p)
+				colors = append(colors, color)
+
+				if closest, ok := dynamic.ClosestPair(); ok {
+					expected, _, _ := NewClosestPairSolverWithMetric(inserted, m.metric).FindClosestPair()
+					matches = matches && closest.Distance == expected
+				}
+				bichromatic, ok := dynamic.ClosestBichromaticPair()
+				expected, found := bruteForceBichromatic(inserted, colors, m.metric)
+				matches = matches && ok == found && (!ok || bichromatic.Distance == expected)
+			}
+		}
+		fmt.Printf("%s metric: dynamic grid matches ClosestPairSolver and red-blue brute force: %v\n", m.name, matches)
+	}
+
+	// Closest red-blue pair in a larger dataset.
+	dynamic := NewDynamicClosestPair(EuclideanDistance)
+	for i, p := range largePoints {
+		dynamic.Insert(p, Color(i%2))
+	}
+	if redBlue, ok := dynamic.ClosestBichromaticPair(); ok {
+		fmt.Printf("Closest red-blue pair in large dataset: %v with a distance of %.2f\n", redBlue.Pair, redBlue.Distance)
+	}
 }
@@ -1,143 +1,379 @@
 package main
 
 import (
+	"bufio"
+	"encoding/csv"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
 )
 
+const (
+	// defaultCutoff is the block dimension at or below which the naive kernel replaces further recursion.
+	defaultCutoff = 64
+	// defaultParallelDepth is the number of top recursion levels whose seven products run on goroutines.
+	defaultParallelDepth = 2
+)
+
+// Number is the set of element types the multiplier supports.
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
 // StrassenMatrixMultiplier is a struct for matrix multiplication using Strassen's algorithm.
-type StrassenMatrixMultiplier struct {
-	MatrixA, MatrixB [][]int
-	AdjustedSize     int
-	OriginalSize     int
+// It multiplies a Rows x Inner matrix by an Inner x Cols matrix. Operands are zero-padded so that
+// every dimension halves evenly for the chosen number of recursion levels; blocks at the bottom
+// of the recursion are multiplied with a cache-friendly naive kernel.
+type StrassenMatrixMultiplier[T Number] struct {
+	MatrixA, MatrixB  [][]T
+	Rows, Inner, Cols int
+	Cutoff            int
+	ParallelDepth     int
+	// reduce maps a value to its canonical representative; nil means no reduction.
+	reduce func(T) T
 }
 
 // NewStrassenMatrixMultiplier is a constructor that initializes a StrassenMatrixMultiplier instance.
-func NewStrassenMatrixMultiplier(matrixA, matrixB [][]int) (*StrassenMatrixMultiplier, error) {
-	// Ensure matrices are square and of the same dimensions.
-	if len(matrixA) != len(matrixB) || len(matrixA) != len(matrixA[0]) || len(matrixB) != len(matrixB[0]) {
-		return nil, errors.New("both matrices must be square and have the same dimensions")
+func NewStrassenMatrixMultiplier[T Number](matrixA, matrixB [][]T) (*StrassenMatrixMultiplier[T], error) {
+	rows, inner, err := matrixDimensions(matrixA)
+	if err != nil {
+		return nil, fmt.Errorf("matrix A: %w", err)
 	}
-
-	originalSize := len(matrixA)
-	adjustedSize := getNextPowerOfTwo(originalSize)
-
-	// Pad matrices to the nearest power of two if necessary.
-	if adjustedSize != originalSize {
-		matrixA = padMatrix(matrixA, adjustedSize)
-		matrixB = padMatrix(matrixB, adjustedSize)
+	innerB, cols, err := matrixDimensions(matrixB)
+	if err != nil {
+		return nil, fmt.Errorf("matrix B: %w", err)
+	}
+	// The number of columns of A must equal the number of rows of B.
+	if inner != innerB {
+		return nil, fmt.Errorf("cannot multiply a %dx%d matrix by a %dx%d matrix", rows, inner, innerB, cols)
 	}
 
-	return &StrassenMatrixMultiplier{
-		MatrixA:      matrixA,
-		MatrixB:      matrixB,
-		AdjustedSize: adjustedSize,
-		OriginalSize: originalSize,
+	return &StrassenMatrixMultiplier[T]{
+		MatrixA:       matrixA,
+		MatrixB:       matrixB,
+		Rows:          rows,
+		Inner:         inner,
+		Cols:          cols,
+		Cutoff:        defaultCutoff,
+		ParallelDepth: defaultParallelDepth,
 	}, nil
 }
 
+// NewModularStrassenMatrixMultiplier creates a multiplier whose arithmetic is carried out modulo a modulus.
+// The modulus must be below 2^31 so that the product of two reduced entries fits in an int64.
+func NewModularStrassenMatrixMultiplier(matrixA, matrixB [][]int64, modulus int64) (*StrassenMatrixMultiplier[int64], error) {
+	if modulus < 2 || modulus >= 1<<31 {
+		return nil, errors.New("modulus must be in the range [2, 2^31)")
+	}
+	reduce := func(x int64) int64 {
+		x %= modulus
+		if x < 0 {
+			x += modulus
+		}
+		return x
+	}
+	multiplier, err := NewStrassenMatrixMultiplier(reduceMatrix(matrixA, reduce), reduceMatrix(matrixB, reduce))
+	if err != nil {
+		return nil, err
+	}
+	multiplier.reduce = reduce
+	return multiplier, nil
+}
+
 // Multiply performs Strassen's matrix multiplication and removes padding if added.
-func (s *StrassenMatrixMultiplier) Multiply() [][]int {
-	result := s.strassenMultiply(s.MatrixA, s.MatrixB)
+func (s *StrassenMatrixMultiplier[T]) Multiply() [][]T {
+	return s.multiplyPadded(s.strassenMultiply)
+}
+
+// MultiplyWinograd performs the Winograd variant of Strassen's algorithm, which needs the same
+// seven products but only fifteen block additions per level instead of eighteen.
+func (s *StrassenMatrixMultiplier[T]) MultiplyWinograd() [][]T {
+	return s.multiplyPadded(s.winogradMultiply)
+}
+
+// multiplyPadded pads the operands for the recursion, runs it and removes the padding from the result.
+func (s *StrassenMatrixMultiplier[T]) multiplyPadded(recurse func(matrixA, matrixB [][]T, levels, depth int) [][]T) [][]T {
+	cutoff := max(s.Cutoff, 1)
+
+	// Count the halvings needed until the smallest dimension fits the cutoff,
+	// then pad every dimension to a multiple of 2^levels.
+	levels := 0
+	for minDim := min(s.Rows, s.Inner, s.Cols); minDim > cutoff; minDim = (minDim + 1) / 2 {
+		levels++
+	}
+	if levels == 0 {
+		return s.naiveMultiply(s.MatrixA, s.MatrixB)
+	}
+	block := 1 << levels
+	paddedA := padMatrix(s.MatrixA, roundUp(s.Rows, block), roundUp(s.Inner, block))
+	paddedB := padMatrix(s.MatrixB, roundUp(s.Inner, block), roundUp(s.Cols, block))
+
+	result := recurse(paddedA, paddedB, levels, 0)
 	// Remove padding if it was added to the matrices
-	return unPadMatrix(result, s.OriginalSize)
+	return unPadMatrix(result, s.Rows, s.Cols)
+}
+
+// NaiveMultiply multiplies the operands with the naive kernel only; it serves as the reference for Multiply.
+func (s *StrassenMatrixMultiplier[T]) NaiveMultiply() [][]T {
+	return s.naiveMultiply(s.MatrixA, s.MatrixB)
 }
 
 // strassenMultiply is a recursive method for performing Strassen's matrix multiplication.
-func (s *StrassenMatrixMultiplier) strassenMultiply(matrixA, matrixB [][]int) [][]int {
-	size := len(matrixA)
-	// Base case: If the matrix is 1x1, return the product directly.
-	if size == 1 {
-		return [][]int{{matrixA[0][0] * matrixB[0][0]}}
+// levels is the number of halvings left before the naive kernel takes over, and depth is the current level.
+func (s *StrassenMatrixMultiplier[T]) strassenMultiply(matrixA, matrixB [][]T, levels, depth int) [][]T {
+	// Base case: blocks small enough for the cache-friendly kernel.
+	if levels == 0 {
+		return s.naiveMultiply(matrixA, matrixB)
 	}
 
 	// Split matrices into quadrants.
 	a11, a12, a21, a22 := splitMatrix(matrixA)
 	b11, b12, b21, b22 := splitMatrix(matrixB)
 
-	// Compute the 7 products required by Strassen's algorithm.
-	product1 := s.strassenMultiply(a11, subtractMatrix(b12, b22))
-	product2 := s.strassenMultiply(addMatrix(a11, a12), b22)
-	product3 := s.strassenMultiply(addMatrix(a21, a22), b11)
-	product4 := s.strassenMultiply(a22, subtractMatrix(b21, b11))
-	product5 := s.strassenMultiply(addMatrix(a11, a22), addMatrix(b11, b22))
-	product6 := s.strassenMultiply(subtractMatrix(a12, a22), addMatrix(b21, b22))
-	product7 := s.strassenMultiply(subtractMatrix(a11, a21), addMatrix(b11, b12))
+	// The operands of the 7 products required by Strassen's algorithm.
+	operands := [7][2][][]T{
+		{a11, s.subtract(b12, b22)},
+		{s.add(a11, a12), b22},
+		{s.add(a21, a22), b11},
+		{a22, s.subtract(b21, b11)},
+		{s.add(a11, a22), s.add(b11, b22)},
+		{s.subtract(a12, a22), s.add(b21, b22)},
+		{s.subtract(a11, a21), s.add(b11, b12)},
+	}
+
+	products := s.computeProducts(operands, levels, depth, s.strassenMultiply)
+	product1, product2, product3, product4, product5, product6, product7 :=
+		products[0], products[1], products[2], products[3], products[4], products[5], products[6]
 
 	// Combine products to form the resulting quadrants.
-	c11 := addMatrix(subtractMatrix(addMatrix(product5, product4), product2), product6)
-	c12 := addMatrix(product1, product2)
-	c21 := addMatrix(product3, product4)
-	c22 := subtractMatrix(subtractMatrix(addMatrix(product5, product1), product3), product7)
+	c11 := s.add(s.subtract(s.add(product5, product4), product2), product6)
+	c12 := s.add(product1, product2)
+	c21 := s.add(product3, product4)
+	c22 := s.subtract(s.subtract(s.add(product5, product1), product3), product7)
+
+	// Combine quadrants into a single matrix.
+	return combineQuadrants(c11, c12, c21, c22)
+}
+
+// winogradMultiply is a recursive method for the Winograd variant of Strassen's algorithm.
+func (s *StrassenMatrixMultiplier[T]) winogradMultiply(matrixA, matrixB [][]T, levels, depth int) [][]T {
+	// Base case: blocks small enough for the cache-friendly kernel.
+	if levels == 0 {
+		return s.naiveMultiply(matrixA, matrixB)
+	}
+
+	// Split matrices into quadrants.
+	a11, a12, a21, a22 := splitMatrix(matrixA)
+	b11, b12, b21, b22 := splitMatrix(matrixB)
+
+	// Eight pre-additions shared between the products.
+	s1 := s.add(a21, a22)
+	s2 := s.subtract(s1, a11)
+	s3 := s.subtract(a11, a21)
+	s4 := s.subtract(a12, s2)
+	t1 := s.subtract(b12, b11)
+	t2 := s.subtract(b22, t1)
+	t3 := s.subtract(b22, b12)
+	t4 := s.subtract(t2, b21)
+
+	operands := [7][2][][]T{
+		{a11, b11},
+		{a12, b21},
+		{s4, b22},
+		{a22, t4},
+		{s1, t1},
+		{s2, t2},
+		{s3, t3},
+	}
+	products := s.computeProducts(operands, levels, depth, s.winogradMultiply)
+
+	// Seven post-additions form the resulting quadrants.
+	u2 := s.add(products[0], products[5])
+	u3 := s.add(u2, products[6])
+	u4 := s.add(u2, products[4])
+	c11 := s.add(products[0], products[1])
+	c12 := s.add(u4, products[2])
+	c21 := s.subtract(u3, products[3])
+	c22 := s.add(u3, products[4])
 
 	// Combine quadrants into a single matrix.
 	return combineQuadrants(c11, c12, c21, c22)
 }
 
-// getNextPowerOfTwo finds the smallest power of two greater than or equal to the given integer.
-func getNextPowerOfTwo(n int) int {
-	power := 1
-	for power < n {
-		power *= 2
+// computeProducts evaluates the seven block products of one recursion level,
+// on goroutines at the top levels of the recursion.
+func (s *StrassenMatrixMultiplier[T]) computeProducts(operands [7][2][][]T, levels, depth int,
+	recurse func(matrixA, matrixB [][]T, levels, depth int) [][]T) [7][][]T {
+	var products [7][][]T
+	if depth < s.ParallelDepth {
+		var wg sync.WaitGroup
+		for i := range operands {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				products[i] = recurse(operands[i][0], operands[i][1], levels-1, depth+1)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range operands {
+			products[i] = recurse(operands[i][0], operands[i][1], levels-1, depth+1)
+		}
 	}
-	return power
+	return products
 }
 
-// padMatrix expands the matrix to the specified size, filling in zeros for the new entries.
-func padMatrix(matrix [][]int, size int) [][]int {
-	padded := make([][]int, size)
-	for i := range padded {
-		padded[i] = make([]int, size)
-		if i < len(matrix) {
-			copy(padded[i], matrix[i])
+// naiveMultiply multiplies two matrices with the i-k-j loop order, which walks rows of B
+// and of the result sequentially and therefore stays friendly to the cache.
+func (s *StrassenMatrixMultiplier[T]) naiveMultiply(matrixA, matrixB [][]T) [][]T {
+	rows, inner := len(matrixA), len(matrixB)
+	cols := 0
+	if inner > 0 {
+		cols = len(matrixB[0])
+	}
+	result := newMatrix[T](rows, cols)
+	for i := 0; i < rows; i++ {
+		resultRow := result[i]
+		for k := 0; k < inner; k++ {
+			a := matrixA[i][k]
+			if a == 0 {
+				continue
+			}
+			rowB := matrixB[k]
+			if s.reduce != nil {
+				for j := range resultRow {
+					resultRow[j] = s.reduce(resultRow[j] + a*rowB[j])
+				}
+			} else {
+				for j := range resultRow {
+					resultRow[j] += a * rowB[j]
+				}
+			}
+		}
+	}
+	return result
+}
+
+// add adds two matrices element-wise, reducing the result if the multiplier is modular.
+func (s *StrassenMatrixMultiplier[T]) add(matrixA, matrixB [][]T) [][]T {
+	result := addMatrix(matrixA, matrixB)
+	if s.reduce != nil {
+		result = reduceMatrix(result, s.reduce)
+	}
+	return result
+}
+
+// subtract subtracts matrixB from matrixA element-wise, reducing the result if the multiplier is modular.
+func (s *StrassenMatrixMultiplier[T]) subtract(matrixA, matrixB [][]T) [][]T {
+	result := subtractMatrix(matrixA, matrixB)
+	if s.reduce != nil {
+		result = reduceMatrix(result, s.reduce)
+	}
+	return result
+}
+
+// matrixDimensions validates that a matrix is non-empty and rectangular and returns its dimensions.
+func matrixDimensions[T Number](matrix [][]T) (int, int, error) {
+	if len(matrix) == 0 || len(matrix[0]) == 0 {
+		return 0, 0, errors.New("matrix must not be empty")
+	}
+	cols := len(matrix[0])
+	for i, row := range matrix {
+		if len(row) != cols {
+			return 0, 0, fmt.Errorf("row %d has %d entries, expected %d", i, len(row), cols)
 		}
 	}
+	return len(matrix), cols, nil
+}
+
+// roundUp rounds n up to the nearest multiple of block.
+func roundUp(n, block int) int {
+	return (n + block - 1) / block * block
+}
+
+// newMatrix allocates a zero matrix backed by a single contiguous slice.
+func newMatrix[T Number](rows, cols int) [][]T {
+	backing := make([]T, rows*cols)
+	matrix := make([][]T, rows)
+	for i := range matrix {
+		matrix[i] = backing[i*cols : (i+1)*cols : (i+1)*cols]
+	}
+	return matrix
+}
+
+// padMatrix expands the matrix to the specified size, filling in zeros for the new entries.
+func padMatrix[T Number](matrix [][]T, rows, cols int) [][]T {
+	padded := newMatrix[T](rows, cols)
+	for i := range matrix {
+		copy(padded[i], matrix[i])
+	}
 	return padded
 }
 
 // unPadMatrix removes extra padding from the matrix, restoring it to the original size.
-func unPadMatrix(matrix [][]int, size int) [][]int {
-	unPadded := make([][]int, size)
-	for i := 0; i < size; i++ {
-		unPadded[i] = matrix[i][:size]
+func unPadMatrix[T Number](matrix [][]T, rows, cols int) [][]T {
+	unPadded := make([][]T, rows)
+	for i := 0; i < rows; i++ {
+		unPadded[i] = matrix[i][:cols]
 	}
 	return unPadded
 }
 
-// splitMatrix divides a matrix into four quadrants.
-func splitMatrix(matrix [][]int) ([][]int, [][]int, [][]int, [][]int) {
-	halfSize := len(matrix) / 2
-	a11 := make([][]int, halfSize)
-	a12 := make([][]int, halfSize)
-	a21 := make([][]int, halfSize)
-	a22 := make([][]int, halfSize)
-	for i := 0; i < halfSize; i++ {
-		a11[i] = matrix[i][:halfSize]
-		a12[i] = matrix[i][halfSize:]
-		a21[i] = matrix[i+halfSize][:halfSize]
-		a22[i] = matrix[i+halfSize][halfSize:]
+// reduceMatrix applies reduce to every entry, returning a new matrix.
+func reduceMatrix[T Number](matrix [][]T, reduce func(T) T) [][]T {
+	result := newMatrix[T](len(matrix), len(matrix[0]))
+	for i, row := range matrix {
+		for j, value := range row {
+			result[i][j] = reduce(value)
+		}
+	}
+	return result
+}
+
+// splitMatrix divides a matrix with even dimensions into four quadrants.
+func splitMatrix[T Number](matrix [][]T) ([][]T, [][]T, [][]T, [][]T) {
+	halfRows := len(matrix) / 2
+	halfCols := len(matrix[0]) / 2
+	a11 := make([][]T, halfRows)
+	a12 := make([][]T, halfRows)
+	a21 := make([][]T, halfRows)
+	a22 := make([][]T, halfRows)
+	for i := 0; i < halfRows; i++ {
+		a11[i] = matrix[i][:halfCols:halfCols]
+		a12[i] = matrix[i][halfCols:]
+		a21[i] = matrix[i+halfRows][:halfCols:halfCols]
+		a22[i] = matrix[i+halfRows][halfCols:]
 	}
 	return a11, a12, a21, a22
 }
 
 // combineQuadrants merges four quadrants into a single matrix.
-func combineQuadrants(c11, c12, c21, c22 [][]int) [][]int {
-	size := len(c11) * 2
-	combined := make([][]int, size)
-	for i := 0; i < size/2; i++ {
-		combined[i] = append(c11[i], c12[i]...)
-		combined[i+size/2] = append(c21[i], c22[i]...)
+func combineQuadrants[T Number](c11, c12, c21, c22 [][]T) [][]T {
+	halfRows := len(c11)
+	combined := newMatrix[T](2*halfRows, len(c11[0])+len(c12[0]))
+	for i := 0; i < halfRows; i++ {
+		copy(combined[i], c11[i])
+		copy(combined[i][len(c11[i]):], c12[i])
+		copy(combined[i+halfRows], c21[i])
+		copy(combined[i+halfRows][len(c21[i]):], c22[i])
 	}
 	return combined
 }
 
 // addMatrix adds two matrices element-wise.
-func addMatrix(matrixA, matrixB [][]int) [][]int {
-	size := len(matrixA)
-	result := make([][]int, size)
-	for i := 0; i < size; i++ {
-		result[i] = make([]int, size)
-		for j := 0; j < size; j++ {
+func addMatrix[T Number](matrixA, matrixB [][]T) [][]T {
+	result := newMatrix[T](len(matrixA), len(matrixA[0]))
+	for i := range result {
+		for j := range result[i] {
 			result[i][j] = matrixA[i][j] + matrixB[i][j]
 		}
 	}
@@ -145,20 +381,374 @@ func addMatrix(matrixA, matrixB [][]int) [][]int {
 }
 
 // subtractMatrix subtracts matrixB from matrixA element-wise.
-func subtractMatrix(matrixA, matrixB [][]int) [][]int {
-	size := len(matrixA)
-	result := make([][]int, size)
-	for i := 0; i < size; i++ {
-		result[i] = make([]int, size)
-		for j := 0; j < size; j++ {
+func subtractMatrix[T Number](matrixA, matrixB [][]T) [][]T {
+	result := newMatrix[T](len(matrixA), len(matrixA[0]))
+	for i := range result {
+		for j := range result[i] {
 			result[i][j] = matrixA[i][j] - matrixB[i][j]
 		}
 	}
 	return result
 }
 
+// ReadMatrixMarket parses a dense matrix from the Matrix Market exchange format.
+// Both the coordinate and the array layouts are supported with real, integer or pattern fields
+// and general, symmetric or skew-symmetric structure.
+func ReadMatrixMarket(reader io.Reader) ([][]float64, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	// The banner identifies the layout, the field and the symmetry.
+	if !scanner.Scan() {
+		return nil, errors.New("matrix market: missing header")
+	}
+	header := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(header) != 5 || header[0] != "%%matrixmarket" || header[1] != "matrix" {
+		return nil, fmt.Errorf("matrix market: invalid header %q", scanner.Text())
+	}
+	layout, field, symmetry := header[2], header[3], header[4]
+	if layout != "coordinate" && layout != "array" {
+		return nil, fmt.Errorf("matrix market: unsupported layout %q", layout)
+	}
+	if field != "real" && field != "integer" && field != "double" && (field != "pattern" || layout != "coordinate") {
+		return nil, fmt.Errorf("matrix market: unsupported field %q", field)
+	}
+	if symmetry != "general" && symmetry != "symmetric" && symmetry != "skew-symmetric" {
+		return nil, fmt.Errorf("matrix market: unsupported symmetry %q", symmetry)
+	}
+
+	// nextFields returns the fields of the next line that is neither blank nor a comment.
+	nextFields := func() ([]string, error) {
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "%") {
+				return strings.Fields(line), nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	sizeFields, err := nextFields()
+	if err != nil {
+		return nil, fmt.Errorf("matrix market: reading size line: %w", err)
+	}
+	sizes, err := parseInts(sizeFields)
+	if err != nil || (layout == "coordinate" && len(sizes) != 3) || (layout == "array" && len(sizes) != 2) {
+		return nil, fmt.Errorf("matrix market: invalid size line %q", strings.Join(sizeFields, " "))
+	}
+	rows, cols := sizes[0], sizes[1]
+	if rows <= 0 || cols <= 0 {
+		return nil, errors.New("matrix market: matrix must not be empty")
+	}
+	matrix := newMatrix[float64](rows, cols)
+
+	// set stores an entry and its mirror image for symmetric matrices.
+	set := func(i, j int, value float64) error {
+		if i < 0 || i >= rows || j < 0 || j >= cols {
+			return fmt.Errorf("matrix market: entry (%d, %d) is out of bounds", i+1, j+1)
+		}
+		matrix[i][j] = value
+		if i != j && symmetry != "general" {
+			if j >= rows || i >= cols {
+				return errors.New("matrix market: symmetric matrix must be square")
+			}
+			if symmetry == "skew-symmetric" {
+				value = -value
+			}
+			matrix[j][i] = value
+		}
+		return nil
+	}
+
+	if layout == "coordinate" {
+		for entry := 0; entry < sizes[2]; entry++ {
+			fields, err := nextFields()
+			if err != nil {
+				return nil, fmt.Errorf("matrix market: reading entry %d: %w", entry+1, err)
+			}
+			if (field == "pattern" && len(fields) != 2) || (field != "pattern" && len(fields) != 3) {
+				return nil, fmt.Errorf("matrix market: invalid entry %q", strings.Join(fields, " "))
+			}
+			indices, err := parseInts(fields[:2])
+			if err != nil {
+				return nil, fmt.Errorf("matrix market: invalid entry %q", strings.Join(fields, " "))
+			}
+			value := 1.0
+			if field != "pattern" {
+				if value, err = strconv.ParseFloat(fields[2], 64); err != nil {
+					return nil, fmt.Errorf("matrix market: invalid value %q", fields[2])
+				}
+			}
+			if err := set(indices[0]-1, indices[1]-1, value); err != nil {
+				return nil, err
+			}
+		}
+		return matrix, nil
+	}
+
+	// Array layout lists values in column-major order; symmetric matrices store only the lower triangle.
+	for j := 0; j < cols; j++ {
+		start := 0
+		if symmetry == "symmetric" {
+			start = j
+		} else if symmetry == "skew-symmetric" {
+			start = j + 1
+		}
+		for i := start; i < rows; i++ {
+			fields, err := nextFields()
+			if err != nil {
+				return nil, fmt.Errorf("matrix market: reading entry (%d, %d): %w", i+1, j+1, err)
+			}
+			value, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil || len(fields) != 1 {
+				return nil, fmt.Errorf("matrix market: invalid value %q", strings.Join(fields, " "))
+			}
+			if err := set(i, j, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// WriteMatrixMarket writes a dense matrix in the Matrix Market array layout.
+func WriteMatrixMarket(writer io.Writer, matrix [][]float64) error {
+	buffered := bufio.NewWriter(writer)
+	rows, cols := len(matrix), 0
+	if rows > 0 {
+		cols = len(matrix[0])
+	}
+	fmt.Fprintln(buffered, "%%MatrixMarket matrix array real general")
+	fmt.Fprintf(buffered, "%d %d\n", rows, cols)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			buffered.WriteString(strconv.FormatFloat(matrix[i][j], 'g', -1, 64))
+			buffered.WriteByte('\n')
+		}
+	}
+	return buffered.Flush()
+}
+
+// ReadCSV parses a dense matrix stored as comma-separated rows of numbers.
+func ReadCSV(reader io.Reader) ([][]float64, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv: matrix must not be empty")
+	}
+	matrix := newMatrix[float64](len(records), len(records[0]))
+	for i, record := range records {
+		for j, cell := range record {
+			if matrix[i][j], err = strconv.ParseFloat(strings.TrimSpace(cell), 64); err != nil {
+				return nil, fmt.Errorf("csv: row %d, column %d: invalid value %q", i+1, j+1, cell)
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// WriteCSV writes a dense matrix as comma-separated rows of numbers.
+func WriteCSV(writer io.Writer, matrix [][]float64) error {
+	csvWriter := csv.NewWriter(writer)
+	for _, row := range matrix {
+		record := make([]string, len(row))
+		for j, value := range row {
+			record[j] = strconv.FormatFloat(value, 'g', -1, 64)
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// ReadMatrixFile reads a matrix from a .mtx (Matrix Market) or .csv file, chosen by extension.
+func ReadMatrixFile(path string) ([][]float64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mtx":
+		return ReadMatrixMarket(file)
+	case ".csv":
+		return ReadCSV(file)
+	default:
+		return nil, fmt.Errorf("unsupported matrix file extension in %q; use .mtx or .csv", path)
+	}
+}
+
+// WriteMatrixFile writes a matrix to a .mtx (Matrix Market) or .csv file, chosen by extension.
+func WriteMatrixFile(path string, matrix [][]float64) error {
+	var write func(io.Writer, [][]float64) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".mtx":
+		write = WriteMatrixMarket
+	case ".csv":
+		write = WriteCSV
+	default:
+		return fmt.Errorf("unsupported matrix file extension in %q; use .mtx or .csv", path)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file, matrix); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// parseInts converts every field to an integer.
+func parseInts(fields []string) ([]int, error) {
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// multiplyFiles reads two matrix files, multiplies them with the chosen algorithm and writes the product.
+// The product goes to standard output in Matrix Market format when outputPath is empty.
+func multiplyFiles(pathA, pathB, outputPath, algorithm string, cutoff int) error {
+	matrixA, err := ReadMatrixFile(pathA)
+	if err != nil {
+		return err
+	}
+	matrixB, err := ReadMatrixFile(pathB)
+	if err != nil {
+		return err
+	}
+	multiplier, err := NewStrassenMatrixMultiplier(matrixA, matrixB)
+	if err != nil {
+		return err
+	}
+	multiplier.Cutoff = cutoff
+
+	var product [][]float64
+	switch algorithm {
+	case "naive":
+		product = multiplier.NaiveMultiply()
+	case "strassen":
+		product = multiplier.Multiply()
+	case "winograd":
+		product = multiplier.MultiplyWinograd()
+	default:
+		return fmt.Errorf("unknown algorithm %q; use naive, strassen or winograd", algorithm)
+	}
+
+	if outputPath == "" {
+		return WriteMatrixMarket(os.Stdout, product)
+	}
+	return WriteMatrixFile(outputPath, product)
+}
+
+// randomMatrix creates a rows x cols matrix of float64 values in [-1, 1).
+func randomMatrix(rng *rand.Rand, rows, cols int) [][]float64 {
+	matrix := newMatrix[float64](rows, cols)
+	for i := range matrix {
+		for j := range matrix[i] {
+			matrix[i][j] = 2*rng.Float64() - 1
+		}
+	}
+	return matrix
+}
+
+// maxAbsDifference returns the largest absolute difference between corresponding entries.
+func maxAbsDifference(matrixA, matrixB [][]float64) float64 {
+	difference := 0.0
+	for i := range matrixA {
+		for j := range matrixA[i] {
+			difference = math.Max(difference, math.Abs(matrixA[i][j]-matrixB[i][j]))
+		}
+	}
+	return difference
+}
+
+// runBenchmarks times the naive kernel against Strassen with several cutoffs on square float64 matrices
+// and reports the first size at which each Strassen configuration wins.
+func runBenchmarks() {
+	rng := rand.New(rand.NewSource(1))
+	cutoffs := []int{32, 64, 128}
+	crossovers := make(map[int]int)
+
+	fmt.Printf("%6s %14s", "n", "naive")
+	for _, cutoff := range cutoffs {
+		fmt.Printf(" %14s", fmt.Sprintf("strassen/%d", cutoff))
+	}
+	fmt.Println()
+
+	for n := 64; n <= 1024; n *= 2 {
+		multiplier, _ := NewStrassenMatrixMultiplier(randomMatrix(rng, n, n), randomMatrix(rng, n, n))
+		timeOf := func(multiply func() [][]float64) int64 {
+			return testing.Benchmark(func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					multiply()
+				}
+			}).NsPerOp()
+		}
+
+		naiveTime := timeOf(multiplier.NaiveMultiply)
+		fmt.Printf("%6d %12dns", n, naiveTime)
+		for _, cutoff := range cutoffs {
+			multiplier.Cutoff = cutoff
+			elapsed := timeOf(multiplier.Multiply)
+			fmt.Printf(" %12dns", elapsed)
+			if _, found := crossovers[cutoff]; !found && elapsed < naiveTime {
+				crossovers[cutoff] = n
+			}
+		}
+		fmt.Println()
+	}
+
+	for _, cutoff := range cutoffs {
+		if n, found := crossovers[cutoff]; found {
+			fmt.Printf("Strassen with cutoff %d beats the naive kernel from n = %d\n", cutoff, n)
+		} else {
+			fmt.Printf("Strassen with cutoff %d does not beat the naive kernel up to n = 1024\n", cutoff)
+		}
+	}
+}
+
 // Main function for testing.
 func main() {
+	bench := flag.Bool("bench", false, "benchmark Strassen against the naive kernel")
+	pathA := flag.String("a", "", "file mode: left operand, a .mtx or .csv file")
+	pathB := flag.String("b", "", "file mode: right operand, a .mtx or .csv file")
+	outputPath := flag.String("output", "", "file mode: .mtx or .csv file for the product (default: Matrix Market on stdout)")
+	algorithm := flag.String("algorithm", "strassen", "file mode: naive, strassen or winograd")
+	cutoff := flag.Int("cutoff", defaultCutoff, "file mode: block size at which the recursion switches to the naive kernel")
+	flag.Parse()
+
+	// File mode: multiply two matrix files and exit.
+	if *pathA != "" || *pathB != "" {
+		if *pathA == "" || *pathB == "" {
+			fmt.Println("Error: both -a and -b are required to multiply matrix files")
+			os.Exit(2)
+		}
+		if err := multiplyFiles(*pathA, *pathB, *outputPath, *algorithm, *cutoff); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	matrixA := [][]int{
 		{1, 2},
 		{3, 4},
@@ -173,6 +763,8 @@ func main() {
 		fmt.Println("Error:", err)
 		return
 	}
+	// A cutoff of one forces the full recursion on this tiny example.
+	multiplier.Cutoff = 1
 
 	result := multiplier.Multiply()
 
@@ -182,10 +774,58 @@ func main() {
 	printMatrix(matrixB)
 	fmt.Println("Result of Strassen's Matrix Multiplication (A * B):")
 	printMatrix(result)
+
+	// Rectangular float64 operands, checked against the naive kernel.
+	rng := rand.New(rand.NewSource(7))
+	floatMultiplier, err := NewStrassenMatrixMultiplier(randomMatrix(rng, 300, 170), randomMatrix(rng, 170, 230))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	floatMultiplier.Cutoff = 16
+	fmt.Printf("300x170 * 170x230 float64, max difference from naive: %.2e\n",
+		maxAbsDifference(floatMultiplier.Multiply(), floatMultiplier.NaiveMultiply()))
+	fmt.Printf("300x170 * 170x230 float64, Winograd variant max difference from naive: %.2e\n",
+		maxAbsDifference(floatMultiplier.MultiplyWinograd(), floatMultiplier.NaiveMultiply()))
+
+	// Modular integer operands.
+	const modulus = 1_000_000_007
+	modA := newMatrix[int64](97, 131)
+	modB := newMatrix[int64](131, 61)
+	for _, m := range [][][]int64{modA, modB} {
+		for i := range m {
+			for j := range m[i] {
+				m[i][j] = rng.Int63()
+			}
+		}
+	}
+	modMultiplier, err := NewModularStrassenMatrixMultiplier(modA, modB, modulus)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	modMultiplier.Cutoff = 8
+	strassen, naive := modMultiplier.Multiply(), modMultiplier.NaiveMultiply()
+	matches := true
+	for i := range strassen {
+		for j := range strassen[i] {
+			matches = matches && strassen[i][j] == naive[i][j]
+		}
+	}
+	fmt.Printf("97x131 * 131x61 modulo %d matches naive: %v\n", modulus, matches)
+
+	// Mismatched inner dimensions are rejected.
+	if _, err := NewStrassenMatrixMultiplier([][]int{{1, 2}}, [][]int{{1, 2}}); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	if *bench {
+		runBenchmarks()
+	}
 }
 
 // printMatrix prints a matrix row by row.
-func printMatrix(matrix [][]int) {
+func printMatrix[T Number](matrix [][]T) {
 	for _, row := range matrix {
 		fmt.Println(row)
 	}
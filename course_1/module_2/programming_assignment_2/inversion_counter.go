@@ -2,7 +2,11 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"iter"
+	"math"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -69,6 +73,202 @@ func (ic *InversionCounter) mergeAndCount(array, tempArray []int, left, mid, rig
 	return invCount
 }
 
+// CountInversions returns the number of pairs i < j with array[i] > array[j] without modifying the input.
+func (ic *InversionCounter) CountInversions(array []int) int {
+	return ic.countInversions(append([]int(nil), array...))
+}
+
+// KendallTauDistance returns the number of element pairs that two rankings order differently.
+// Both slices must be permutations of the same distinct elements.
+func (ic *InversionCounter) KendallTauDistance(first, second []int) (int, error) {
+	if len(first) != len(second) {
+		return 0, errors.New("rankings must have the same length")
+	}
+
+	// Map each element to its position in the first ranking.
+	position := make(map[int]int, len(first))
+	for i, element := range first {
+		if _, found := position[element]; found {
+			return 0, fmt.Errorf("element %d appears more than once in the first ranking", element)
+		}
+		position[element] = i
+	}
+
+	// Relabel the second ranking by those positions; every inversion is a discordant pair.
+	relabeled := make([]int, len(second))
+	seen := make(map[int]bool, len(second))
+	for i, element := range second {
+		pos, found := position[element]
+		if !found {
+			return 0, fmt.Errorf("element %d of the second ranking is missing from the first", element)
+		}
+		if seen[element] {
+			return 0, fmt.Errorf("element %d appears more than once in the second ranking", element)
+		}
+		seen[element] = true
+		relabeled[i] = pos
+	}
+	return ic.countInversions(relabeled), nil
+}
+
+// CountSignificantInversions returns the number of pairs i < j with array[i] > 2*array[j]
+// without modifying the input.
+func (ic *InversionCounter) CountSignificantInversions(array []int) int {
+	sorted := append([]int(nil), array...)
+	tempArray := make([]int, len(sorted))
+	return ic.countSignificantRecursive(sorted, tempArray, 0, len(sorted)-1)
+}
+
+// countSignificantRecursive counts significant inversions while merge sorting the subarray.
+func (ic *InversionCounter) countSignificantRecursive(array, tempArray []int, left, right int) int {
+	if left >= right {
+		return 0
+	}
+
+	mid := (left + right) / 2
+
+	count := ic.countSignificantRecursive(array, tempArray, left, mid)
+	count += ic.countSignificantRecursive(array, tempArray, mid+1, right)
+
+	// Both halves are sorted: a single two-pointer pass counts the significant split pairs.
+	j := mid + 1
+	for i := left; i <= mid; i++ {
+		for j <= right && exceedsTwice(array[i], array[j]) {
+			j++
+		}
+		count += j - (mid + 1)
+	}
+
+	// Merge the halves; the plain inversion count is not needed here.
+	ic.mergeAndCount(array, tempArray, left, mid, right)
+
+	return count
+}
+
+// exceedsTwice reports whether a > 2*b without overflowing. a > 2*b is equivalent to a - b > b; the
+// difference only overflows when a and b have opposite signs, and then the answer follows from the signs.
+func exceedsTwice(a, b int) bool {
+	if b >= 0 {
+		return a > b && a-b > b
+	}
+	return a >= 0 || a-b > b
+}
+
+// indexedValue is an array element together with its original position.
+type indexedValue struct {
+	value int
+	index int
+}
+
+// Inversions returns an iterator over every inversion of array as a pair of original indices (i, j)
+// with i < j and array[i] > array[j]. Pairs are produced by the merge step, so they are not in
+// lexicographic order. The input is not modified and iteration may stop early.
+func (ic *InversionCounter) Inversions(array []int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		items := make([]indexedValue, len(array))
+		for i, value := range array {
+			items[i] = indexedValue{value: value, index: i}
+		}
+		tempItems := make([]indexedValue, len(items))
+		ic.enumerateRecursive(items, tempItems, 0, len(items)-1, yield)
+	}
+}
+
+// ForEachInversion calls visit for every inversion (i, j) of array until visit returns false.
+func (ic *InversionCounter) ForEachInversion(array []int, visit func(i, j int) bool) {
+	ic.Inversions(array)(visit)
+}
+
+// enumerateRecursive merge sorts items and reports split inversions during each merge.
+// It returns false once the consumer has asked to stop.
+func (ic *InversionCounter) enumerateRecursive(items, tempItems []indexedValue, left, right int, yield func(int, int) bool) bool {
+	if left >= right {
+		return true
+	}
+
+	mid := (left + right) / 2
+
+	if !ic.enumerateRecursive(items, tempItems, left, mid, yield) ||
+		!ic.enumerateRecursive(items, tempItems, mid+1, right, yield) {
+		return false
+	}
+
+	i, j, k := left, mid+1, left
+	for i <= mid && j <= right {
+		if items[i].value <= items[j].value {
+			tempItems[k] = items[i]
+			i++
+		} else {
+			// Every remaining element of the left half forms an inversion with items[j].
+			for l := i; l <= mid; l++ {
+				if !yield(items[l].index, items[j].index) {
+					return false
+				}
+			}
+			tempItems[k] = items[j]
+			j++
+		}
+		k++
+	}
+	k += copy(tempItems[k:], items[i:mid+1])
+	copy(tempItems[k:], items[j:right+1])
+	copy(items[left:right+1], tempItems[left:right+1])
+
+	return true
+}
+
+// StreamingInversionCounter counts inversions of values that arrive one at a time,
+// using a Fenwick (binary indexed) tree over a fixed range of values.
+type StreamingInversionCounter struct {
+	minValue   int
+	tree       []int
+	total      int
+	inversions int
+}
+
+// NewStreamingInversionCounter creates a counter for values in the inclusive range [minValue, maxValue].
+func NewStreamingInversionCounter(minValue, maxValue int) (*StreamingInversionCounter, error) {
+	if minValue > maxValue {
+		return nil, errors.New("minValue must not exceed maxValue")
+	}
+	return &StreamingInversionCounter{
+		minValue: minValue,
+		tree:     make([]int, maxValue-minValue+2),
+	}, nil
+}
+
+// Add appends a value to the sequence and returns the number of earlier values greater than it,
+// which is the number of new inversions it creates.
+func (sc *StreamingInversionCounter) Add(value int) (int, error) {
+	position := value - sc.minValue + 1
+	if position < 1 || position >= len(sc.tree) {
+		return 0, fmt.Errorf("value %d is outside the counter's range", value)
+	}
+
+	// Earlier values greater than this one = all earlier values - those less than or equal to it.
+	newInversions := sc.total - sc.prefixSum(position)
+	for i := position; i < len(sc.tree); i += i & -i {
+		sc.tree[i]++
+	}
+	sc.total++
+	sc.inversions += newInversions
+	return newInversions, nil
+}
+
+// prefixSum returns how many values added so far map to positions 1..position.
+func (sc *StreamingInversionCounter) prefixSum(position int) int {
+	sum := 0
+	for i := position; i > 0; i -= i & -i {
+		sum += sc.tree[i]
+	}
+	return sum
+}
+
+// Count returns the number of inversions among all values added so far.
+func (sc *StreamingInversionCounter) Count() int {
+	return sc.inversions
+}
+
 func main() {
 	// Get the path to the current directory where the script is located.
 	currentDir, err := os.Getwd()
@@ -103,10 +303,73 @@ func main() {
 		fmt.Println("Error reading file:", err)
 		return
 	}
+	if len(inputArray) == 0 {
+		fmt.Println("Error: no integers found in", filePath)
+		return
+	}
 
 	// Create an instance of InversionCounter and count inversions.
 	counter := InversionCounter{}
-	inversionCount := counter.countInversions(inputArray)
+	inversionCount := counter.CountInversions(inputArray)
 
 	fmt.Println("Number of inversions:", inversionCount)
+	fmt.Println("Number of significant inversions:", counter.CountSignificantInversions(inputArray))
+
+	// Count the same inversions again as if the values arrived one at a time.
+	minValue, maxValue := inputArray[0], inputArray[0]
+	for _, num := range inputArray {
+		minValue = min(minValue, num)
+		maxValue = max(maxValue, num)
+	}
+	streaming, err := NewStreamingInversionCounter(minValue, maxValue)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	for _, num := range inputArray {
+		if _, err := streaming.Add(num); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	}
+	fmt.Println("Number of inversions (Fenwick tree):", streaming.Count())
+
+	// Enumerate the inversion pairs of a small array.
+	small := []int{3, 1, 4, 1, 5, 9, 2, 6}
+	fmt.Printf("Inversions of %v:", small)
+	for i, j := range counter.Inversions(small) {
+		fmt.Printf(" (%d,%d)", i, j)
+	}
+	fmt.Println()
+
+	// Compare two rankings of the same items.
+	distance, err := counter.KendallTauDistance([]int{1, 2, 3, 4, 5}, []int{3, 4, 1, 2, 5})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Kendall tau distance:", distance)
+
+	// Doubling values near the int limits would overflow; only 4 of these pairs are significant.
+	extremes := []int{math.MaxInt, math.MaxInt/2 + 1, -math.MaxInt, 1}
+	fmt.Printf("Significant inversions of %v: %d\n", extremes, counter.CountSignificantInversions(extremes))
+
+	// Check significant inversions against comparing every pair, on random arrays with negative values.
+	matches := true
+	for trial := 0; trial < 500; trial++ {
+		values := make([]int, rand.Intn(40))
+		for i := range values {
+			values[i] = rand.Intn(201) - 100
+		}
+		expected := 0
+		for i := range values {
+			for j := i + 1; j < len(values); j++ {
+				if values[i] > 2*values[j] {
+					expected++
+				}
+			}
+		}
+		matches = matches && counter.CountSignificantInversions(values) == expected
+	}
+	fmt.Println("Significant inversions match brute force:", matches)
 }
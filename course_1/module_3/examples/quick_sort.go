@@ -1,14 +1,24 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"math/bits"
+	"math/rand"
+	"os"
+	"strconv"
 )
 
-// QuickSort is a struct that holds an array to be sorted and a counter for the number of comparisons.
-// The `comparisons` counter tracks the total number of comparisons made during the sorting process.
+// introsortInsertionThreshold is the subarray length at or below which introsort switches to insertion sort.
+const introsortInsertionThreshold = 16
+
+// QuickSort is a struct that holds an array to be sorted and counters for comparisons and swaps.
+// The `comparisons` counter tracks the total number of comparisons made during the sorting process,
+// and the `swaps` counter tracks the number of element exchanges.
 type QuickSort struct {
 	array       []int
 	comparisons int
+	swaps       int
 }
 
 // NewQuickSort is a constructor function that initializes a new QuickSort instance.
@@ -18,127 +28,350 @@ func NewQuickSort(arr []int) *QuickSort {
 	return &QuickSort{array: append([]int(nil), arr...), comparisons: 0}
 }
 
+// PivotStrategy chooses the pivot for a partitioning step.
+// ChoosePivot returns the index of the pivot element within array[low..high]; it must not modify the array.
+type PivotStrategy interface {
+	ChoosePivot(array []int, low, high int) int
+}
+
+// FirstPivot selects the first element of the subarray as the pivot.
+type FirstPivot struct{}
+
+// ChoosePivot returns the index of the first element.
+func (FirstPivot) ChoosePivot(array []int, low, high int) int {
+	return low
+}
+
+// LastPivot selects the last element of the subarray as the pivot.
+type LastPivot struct{}
+
+// ChoosePivot returns the index of the last element.
+func (LastPivot) ChoosePivot(array []int, low, high int) int {
+	return high
+}
+
+// MedianOfThreePivot selects the median of the first, middle, and last elements as the pivot.
+type MedianOfThreePivot struct{}
+
+// ChoosePivot returns the index of the median of the first, middle, and last elements.
+func (MedianOfThreePivot) ChoosePivot(array []int, low, high int) int {
+	return medianOfThree(array, low, (low+high)/2, high)
+}
+
+// RandomPivot selects a uniformly random element of the subarray as the pivot.
+type RandomPivot struct {
+	rng *rand.Rand
+}
+
+// NewRandomPivot creates a RandomPivot with its own random source, so runs with the same seed are reproducible.
+func NewRandomPivot(seed int64) *RandomPivot {
+	return &RandomPivot{rng: rand.New(rand.NewSource(seed))}
+}
+
+// ChoosePivot returns the index of a random element.
+func (rp *RandomPivot) ChoosePivot(array []int, low, high int) int {
+	return low + rp.rng.Intn(high-low+1)
+}
+
+// NintherPivot selects Tukey's ninther: the median of the medians of three evenly spaced triples.
+// Subarrays with fewer than nine elements fall back to the median-of-three.
+type NintherPivot struct{}
+
+// ChoosePivot returns the index of the ninther.
+func (NintherPivot) ChoosePivot(array []int, low, high int) int {
+	n := high - low + 1
+	if n < 9 {
+		return medianOfThree(array, low, (low+high)/2, high)
+	}
+	step := n / 8
+	mid := (low + high) / 2
+	first := medianOfThree(array, low, low+step, low+2*step)
+	middle := medianOfThree(array, mid-step, mid, mid+step)
+	last := medianOfThree(array, high-2*step, high-step, high)
+	return medianOfThree(array, first, middle, last)
+}
+
+// medianOfThree returns whichever of the indices i, j and k holds the median value.
+// Candidates are ordered by value with ties kept in argument order, as in the original median-of-three rule.
+func medianOfThree(array []int, i, j, k int) int {
+	// Create a list of pivot candidates with their values and indices.
+	pivotCandidates := []struct {
+		value int
+		index int
+	}{
+		{array[i], i},
+		{array[j], j},
+		{array[k], k},
+	}
+
+	// Sort the pivot candidates by value to determine the median.
+	for a := 0; a < len(pivotCandidates)-1; a++ {
+		for b := a + 1; b < len(pivotCandidates); b++ {
+			if pivotCandidates[a].value > pivotCandidates[b].value {
+				pivotCandidates[a], pivotCandidates[b] = pivotCandidates[b], pivotCandidates[a]
+			}
+		}
+	}
+	return pivotCandidates[1].index
+}
+
+// Sort sorts the array with quicksort, choosing every pivot with the given strategy.
+func (qs *QuickSort) Sort(strategy PivotStrategy) {
+	qs.quicksort(0, len(qs.array)-1, strategy)
+}
+
+// SortThreeWay sorts the array with quicksort using a three-way (Dutch national flag) partition,
+// which groups all elements equal to the pivot and skips them in the recursion.
+// It is much faster than Sort on inputs with many duplicates.
+func (qs *QuickSort) SortThreeWay(strategy PivotStrategy) {
+	qs.quicksortThreeWay(0, len(qs.array)-1, strategy)
+}
+
+// SortIntro sorts the array with introsort: quicksort with pivots from the strategy, switching to
+// heapsort once the recursion depth exceeds 2*log2(n) and to insertion sort on small ranges.
+// This bounds the worst case at O(n log n) even for adversarial inputs.
+func (qs *QuickSort) SortIntro(strategy PivotStrategy) {
+	n := len(qs.array)
+	if n < 2 {
+		return
+	}
+	qs.introsort(0, n-1, 2*(bits.Len(uint(n))-1), strategy)
+}
+
+// SortDualPivot sorts the array with Yaroslavskiy's dual-pivot quicksort, which partitions
+// around the first and last elements into three parts at every step.
+func (qs *QuickSort) SortDualPivot() {
+	qs.dualPivotQuicksort(0, len(qs.array)-1)
+}
+
 // SortWithFirstPivot sorts the array using the first element as the pivot.
-// It calls the `quicksortFirst` method, which performs the recursive quicksort
-// operation based on the first element as the pivot.
 func (qs *QuickSort) SortWithFirstPivot() {
-	qs.quicksortFirst(0, len(qs.array)-1)
+	qs.Sort(FirstPivot{})
 }
 
 // SortWithLastPivot sorts the array using the last element as the pivot.
-// It calls the `quicksortLast` method, which performs the recursive quicksort
-// operation based on the last element as the pivot.
 func (qs *QuickSort) SortWithLastPivot() {
-	qs.quicksortLast(0, len(qs.array)-1)
+	qs.Sort(LastPivot{})
 }
 
 // SortWithMedianPivot sorts the array using the median-of-three as the pivot.
-// It calls the `quicksortMedian` method, which performs the recursive quicksort
-// operation based on the median of the first, middle, and last elements.
 func (qs *QuickSort) SortWithMedianPivot() {
-	qs.quicksortMedian(0, len(qs.array)-1)
+	qs.Sort(MedianOfThreePivot{})
 }
 
-// quicksortFirst is a recursive method that sorts the array using the first element as the pivot.
-// It partitions the array around the first element, then recursively sorts the left and right partitions.
-func (qs *QuickSort) quicksortFirst(low, high int) {
+// quicksort is a recursive method that sorts array[low..high] around pivots chosen by the strategy.
+// It partitions the array around the pivot, then recursively sorts the left and right partitions.
+func (qs *QuickSort) quicksort(low, high int, strategy PivotStrategy) {
 	if low < high {
+		// Move the chosen pivot to the beginning of the subarray.
+		pivotIndex := strategy.ChoosePivot(qs.array, low, high)
+		qs.swap(low, pivotIndex)
 		// Partition the array around the pivot and get the pivot index.
-		pivotIndex := qs.partitionFirst(low, high)
+		pivotIndex = qs.partition(low, high)
 		// Recursively sort the elements to the left of the pivot.
-		qs.quicksortFirst(low, pivotIndex-1)
+		qs.quicksort(low, pivotIndex-1, strategy)
 		// Recursively sort the elements to the right of the pivot.
-		qs.quicksortFirst(pivotIndex+1, high)
+		qs.quicksort(pivotIndex+1, high, strategy)
 	}
 }
 
-// partitionFirst partitions the array using the first element as the pivot.
+// partition partitions the array using the first element as the pivot.
 // It places elements smaller than the pivot to its left and larger elements to its right.
 // It returns the final index of the pivot after partitioning.
-func (qs *QuickSort) partitionFirst(low, high int) int {
-	pivot := qs.array[low] // Select the first element as the pivot.
+func (qs *QuickSort) partition(low, high int) int {
+	pivot := qs.array[low] // The pivot has already been moved to the first position.
 	i := low + 1           // Initialize pointer for the greater element.
 
 	// Iterate over the elements and rearrange them based on the pivot value.
 	for j := low + 1; j <= high; j++ {
 		if qs.array[j] < pivot {
 			// Swap elements to bring smaller elements to the left.
-			qs.array[i], qs.array[j] = qs.array[j], qs.array[i]
+			qs.swap(i, j)
 			i++
 		}
 	}
 	// Place the pivot in its correct sorted position.
-	qs.array[low], qs.array[i-1] = qs.array[i-1], qs.array[low]
+	qs.swap(low, i-1)
 	// Update the comparison count with the number of elements in the current partition.
 	qs.comparisons += high - low
 	// Return the index of the pivot after partitioning.
 	return i - 1
 }
 
-// quicksortLast is a recursive method that sorts the array using the last element as the pivot.
-// It swaps the last element with the first element, then partitions and recursively sorts the subarrays.
-func (qs *QuickSort) quicksortLast(low, high int) {
+// quicksortThreeWay is a recursive method that sorts array[low..high] with three-way partitioning.
+func (qs *QuickSort) quicksortThreeWay(low, high int, strategy PivotStrategy) {
 	if low < high {
-		// Partition the array around the pivot and get the pivot index.
-		pivotIndex := qs.partitionLast(low, high)
-		// Recursively sort the elements to the left of the pivot.
-		qs.quicksortLast(low, pivotIndex-1)
-		// Recursively sort the elements to the right of the pivot.
-		qs.quicksortLast(pivotIndex+1, high)
+		pivotIndex := strategy.ChoosePivot(qs.array, low, high)
+		lt, gt := qs.partitionThreeWay(low, high, pivotIndex)
+		// Elements in array[lt..gt] equal the pivot and are already in place.
+		qs.quicksortThreeWay(low, lt-1, strategy)
+		qs.quicksortThreeWay(gt+1, high, strategy)
 	}
 }
 
-// partitionLast partitions the array using the last element as the pivot.
-// It swaps the last element to the beginning, then calls `partitionFirst` to perform the partitioning.
-func (qs *QuickSort) partitionLast(low, high int) int {
-	// Swap the last element with the first to use it as the pivot.
-	qs.array[low], qs.array[high] = qs.array[high], qs.array[low]
-	// Call partitionFirst to partition around the new pivot (initially the last element).
-	return qs.partitionFirst(low, high)
+// partitionThreeWay rearranges array[low..high] into elements less than, equal to and greater than
+// the pivot, and returns the bounds [lt, gt] of the equal block.
+// Every element is compared with the pivot once or twice, and each comparison is counted.
+func (qs *QuickSort) partitionThreeWay(low, high, pivotIndex int) (int, int) {
+	pivot := qs.array[pivotIndex]
+	lt, i, gt := low, low, high
+
+	// Invariant: array[low..lt-1] < pivot, array[lt..i-1] == pivot, array[gt+1..high] > pivot.
+	for i <= gt {
+		qs.comparisons++
+		if qs.array[i] < pivot {
+			qs.swap(lt, i)
+			lt++
+			i++
+			continue
+		}
+		qs.comparisons++
+		if qs.array[i] > pivot {
+			qs.swap(i, gt)
+			gt--
+		} else {
+			i++
+		}
+	}
+	return lt, gt
 }
 
-// quicksortMedian is a recursive method that sorts the array using the median-of-three as the pivot.
-// It selects the median of the first, middle, and last elements as the pivot, then partitions and sorts.
-func (qs *QuickSort) quicksortMedian(low, high int) {
-	if low < high {
-		// Partition the array around the median-of-three pivot and get the pivot index.
-		pivotIndex := qs.partitionMedian(low, high)
-		// Recursively sort the elements to the left of the pivot.
-		qs.quicksortMedian(low, pivotIndex-1)
-		// Recursively sort the elements to the right of the pivot.
-		qs.quicksortMedian(pivotIndex+1, high)
+// introsort is a recursive method that quicksorts array[low..high] until depthLimit reaches zero,
+// then hands the range to heapsort; ranges of introsortInsertionThreshold elements or fewer use insertion sort.
+func (qs *QuickSort) introsort(low, high, depthLimit int, strategy PivotStrategy) {
+	for high-low+1 > introsortInsertionThreshold {
+		if depthLimit == 0 {
+			qs.heapSort(low, high)
+			return
+		}
+		depthLimit--
+
+		pivotIndex := strategy.ChoosePivot(qs.array, low, high)
+		qs.swap(low, pivotIndex)
+		pivotIndex = qs.partition(low, high)
+
+		// Recurse into the smaller side and loop on the larger one to keep the stack shallow.
+		if pivotIndex-low < high-pivotIndex {
+			qs.introsort(low, pivotIndex-1, depthLimit, strategy)
+			low = pivotIndex + 1
+		} else {
+			qs.introsort(pivotIndex+1, high, depthLimit, strategy)
+			high = pivotIndex - 1
+		}
 	}
+	qs.insertionSort(low, high)
 }
 
-// partitionMedian partitions the array using the median of the first, middle, and last elements as the pivot.
-// It finds the median among these three elements, swaps it to the beginning, and then partitions the array.
-func (qs *QuickSort) partitionMedian(low, high int) int {
-	mid := (low + high) / 2 // Calculate the middle index.
+// insertionSort sorts array[low..high] by swapping each element left until it is in place.
+func (qs *QuickSort) insertionSort(low, high int) {
+	for i := low + 1; i <= high; i++ {
+		for j := i; j > low; j-- {
+			qs.comparisons++
+			if qs.array[j-1] <= qs.array[j] {
+				break
+			}
+			qs.swap(j-1, j)
+		}
+	}
+}
 
-	// Create a list of pivot candidates (first, middle, and last) with their values and indices.
-	pivotCandidates := []struct {
-		value int
-		index int
-	}{
-		{qs.array[low], low},
-		{qs.array[mid], mid},
-		{qs.array[high], high},
+// heapSort sorts array[low..high] in place by building a max-heap and repeatedly extracting the maximum.
+func (qs *QuickSort) heapSort(low, high int) {
+	n := high - low + 1
+	for i := n/2 - 1; i >= 0; i-- {
+		qs.siftDown(low, i, n)
 	}
+	for end := n - 1; end > 0; end-- {
+		qs.swap(low, low+end)
+		qs.siftDown(low, 0, end)
+	}
+}
 
-	// Sort the pivot candidates by value to determine the median.
-	for i := 0; i < len(pivotCandidates)-1; i++ {
-		for j := i + 1; j < len(pivotCandidates); j++ {
-			if pivotCandidates[i].value > pivotCandidates[j].value {
-				pivotCandidates[i], pivotCandidates[j] = pivotCandidates[j], pivotCandidates[i]
+// siftDown restores the max-heap property for the heap of size n stored at array[low..], starting at root.
+func (qs *QuickSort) siftDown(low, root, n int) {
+	for {
+		child := 2*root + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n {
+			qs.comparisons++
+			if qs.array[low+child+1] > qs.array[low+child] {
+				child++
 			}
 		}
+		qs.comparisons++
+		if qs.array[low+root] >= qs.array[low+child] {
+			return
+		}
+		qs.swap(low+root, low+child)
+		root = child
 	}
+}
+
+// dualPivotQuicksort is a recursive method implementing Yaroslavskiy's dual-pivot partitioning:
+// with pivots p <= q taken from the ends, it forms the parts < p, between p and q, and > q.
+func (qs *QuickSort) dualPivotQuicksort(low, high int) {
+	if low >= high {
+		return
+	}
+
+	// Order the two pivots.
+	qs.comparisons++
+	if qs.array[low] > qs.array[high] {
+		qs.swap(low, high)
+	}
+	p, q := qs.array[low], qs.array[high]
+
+	// Invariant: array[low+1..l-1] < p, array[l..k-1] in [p, q], array[g+1..high-1] > q.
+	l, k, g := low+1, low+1, high-1
+	for k <= g {
+		qs.comparisons++
+		if qs.array[k] < p {
+			qs.swap(k, l)
+			l++
+		} else {
+			qs.comparisons++
+			if qs.array[k] > q {
+				// Skip elements at the right end that already belong there.
+				for k < g {
+					qs.comparisons++
+					if qs.array[g] <= q {
+						break
+					}
+					g--
+				}
+				qs.swap(k, g)
+				g--
+				qs.comparisons++
+				if qs.array[k] < p {
+					qs.swap(k, l)
+					l++
+				}
+			}
+		}
+		k++
+	}
+	l--
+	g++
 
-	// Select the median element as the pivot and swap it to the beginning of the array.
-	pivotIndex := pivotCandidates[1].index
-	qs.array[low], qs.array[pivotIndex] = qs.array[pivotIndex], qs.array[low]
-	// Use the partitionFirst method to partition around the median pivot.
-	return qs.partitionFirst(low, high)
+	// Move the pivots into their final positions.
+	qs.swap(low, l)
+	qs.swap(high, g)
+
+	qs.dualPivotQuicksort(low, l-1)
+	qs.dualPivotQuicksort(l+1, g-1)
+	qs.dualPivotQuicksort(g+1, high)
+}
+
+// swap exchanges two elements and counts the exchange. Swapping an element with itself, as when the
+// pivot is already in place, is not an exchange and is not counted.
+func (qs *QuickSort) swap(i, j int) {
+	if i == j {
+		return
+	}
+	qs.array[i], qs.array[j] = qs.array[j], qs.array[i]
+	qs.swaps++
 }
 
 // GetComparisons returns the total number of comparisons made during the sorting process.
@@ -146,6 +379,64 @@ func (qs *QuickSort) GetComparisons() int {
 	return qs.comparisons
 }
 
+// GetSwaps returns the total number of element exchanges made during the sorting process.
+func (qs *QuickSort) GetSwaps() int {
+	return qs.swaps
+}
+
+// isSorted reports whether the array is in non-decreasing order.
+func (qs *QuickSort) isSorted() bool {
+	for i := 1; i < len(qs.array); i++ {
+		if qs.array[i-1] > qs.array[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// loadIntegers reads one integer per line from a file.
+func loadIntegers(filename string) ([]int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var arr []int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		num, err := strconv.Atoi(scanner.Text())
+		if err != nil {
+			continue // Skip invalid lines.
+		}
+		arr = append(arr, num)
+	}
+	return arr, scanner.Err()
+}
+
+// compareModes sorts the same input with every mode and prints comparisons and swaps.
+func compareModes(name string, array []int) {
+	modes := []struct {
+		name string
+		sort func(qs *QuickSort)
+	}{
+		{"quicksort, first pivot", func(qs *QuickSort) { qs.Sort(FirstPivot{}) }},
+		{"quicksort, median-of-three", func(qs *QuickSort) { qs.Sort(MedianOfThreePivot{}) }},
+		{"three-way, median-of-three", func(qs *QuickSort) { qs.SortThreeWay(MedianOfThreePivot{}) }},
+		{"introsort, first pivot", func(qs *QuickSort) { qs.SortIntro(FirstPivot{}) }},
+		{"introsort, median-of-three", func(qs *QuickSort) { qs.SortIntro(MedianOfThreePivot{}) }},
+		{"dual-pivot", func(qs *QuickSort) { qs.SortDualPivot() }},
+	}
+
+	fmt.Printf("%s (%d elements):\n", name, len(array))
+	for _, mode := range modes {
+		qs := NewQuickSort(array)
+		mode.sort(qs)
+		fmt.Printf("  %-28s comparisons %12d  swaps %12d  sorted %v\n",
+			mode.name, qs.GetComparisons(), qs.GetSwaps(), qs.isSorted())
+	}
+}
+
 // GetSortedArray returns the sorted array after performing quicksort.
 // This allows access to the final sorted order after any of the sorting methods is called.
 func (qs *QuickSort) GetSortedArray() []int {
@@ -158,21 +449,55 @@ func main() {
 	// Example array to be sorted.
 	array := []int{3, 8, 2, 5, 1, 4, 7, 6}
 
-	// Create a QuickSort instance and sort the array using the first element as the pivot.
-	qsFirst := NewQuickSort(array)
-	qsFirst.SortWithFirstPivot()
-	fmt.Println("Sorted array with first element as pivot:", qsFirst.GetSortedArray())
-	fmt.Println("Total comparisons with first element as pivot:", qsFirst.GetComparisons())
-
-	// Create a QuickSort instance and sort the array using the last element as the pivot.
-	qsLast := NewQuickSort(array)
-	qsLast.SortWithLastPivot()
-	fmt.Println("Sorted array with last element as pivot:", qsLast.GetSortedArray())
-	fmt.Println("Total comparisons with last element as pivot:", qsLast.GetComparisons())
-
-	// Create a QuickSort instance and sort the array using the median-of-three as the pivot.
-	qsMedian := NewQuickSort(array)
-	qsMedian.SortWithMedianPivot()
-	fmt.Println("Sorted array with median-of-three as pivot:", qsMedian.GetSortedArray())
-	fmt.Println("Total comparisons with median-of-three as pivot:", qsMedian.GetComparisons())
+	strategies := []struct {
+		name     string
+		strategy PivotStrategy
+	}{
+		{"first element", FirstPivot{}},
+		{"last element", LastPivot{}},
+		{"median-of-three", MedianOfThreePivot{}},
+		{"random element", NewRandomPivot(1)},
+		{"Tukey's ninther", NintherPivot{}},
+	}
+
+	// Sort the array with every pivot strategy.
+	for _, s := range strategies {
+		qs := NewQuickSort(array)
+		qs.Sort(s.strategy)
+		fmt.Printf("Sorted array with %s as pivot: %v\n", s.name, qs.GetSortedArray())
+		fmt.Printf("Total comparisons with %s as pivot: %d\n", s.name, qs.GetComparisons())
+	}
+
+	// Three-way partitioning on an input with many duplicates.
+	duplicates := make([]int, 1000)
+	for i := range duplicates {
+		duplicates[i] = i % 3
+	}
+	for _, s := range strategies {
+		twoWay := NewQuickSort(duplicates)
+		twoWay.Sort(s.strategy)
+		threeWay := NewQuickSort(duplicates)
+		threeWay.SortThreeWay(s.strategy)
+		fmt.Printf("1000 values in {0, 1, 2} with %s as pivot: two-way %d comparisons, three-way %d comparisons\n",
+			s.name, twoWay.GetComparisons(), threeWay.GetComparisons())
+	}
+
+	// Compare every mode on the course datasets (run from the repository root) and on an adversarial input:
+	// already sorted data drives the first-element pivot to quadratic time, which introsort avoids.
+	for _, dataset := range []string{
+		"course_1/module_2/programming_assignment_2/IntegerArray.txt",
+		"course_1/module_3/programming_assignment_3/QuickSort.txt",
+	} {
+		data, err := loadIntegers(dataset)
+		if err != nil {
+			fmt.Println("Error loading data:", err)
+			continue
+		}
+		compareModes(dataset, data)
+	}
+	sorted := make([]int, 20000)
+	for i := range sorted {
+		sorted[i] = i
+	}
+	compareModes("already sorted input", sorted)
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"math/rand"
 	"os"
 	"strconv"
 )
@@ -19,89 +20,226 @@ func NewQuickSort(arr []int) *QuickSort {
 	return &QuickSort{array: append([]int(nil), arr...), comparisons: 0}
 }
 
-// QuickSortFirst sorts the array using the first element as the pivot.
-// It recursively divides the array around the chosen pivot.
-func (qs *QuickSort) QuickSortFirst(low, high int) {
-	if low < high {
-		pivotIndex := qs.PartitionFirst(low, high)
-		qs.QuickSortFirst(low, pivotIndex-1)  // Sort elements before the pivot.
-		qs.QuickSortFirst(pivotIndex+1, high) // Sort elements after the pivot.
+// PivotStrategy chooses the pivot for a partitioning step.
+// ChoosePivot returns the index of the pivot element within array[low..high]; it must not modify the array.
+type PivotStrategy interface {
+	ChoosePivot(array []int, low, high int) int
+}
+
+// FirstPivot selects the first element of the subarray as the pivot.
+type FirstPivot struct{}
+
+// ChoosePivot returns the index of the first element.
+func (FirstPivot) ChoosePivot(array []int, low, high int) int {
+	return low
+}
+
+// LastPivot selects the last element of the subarray as the pivot.
+type LastPivot struct{}
+
+// ChoosePivot returns the index of the last element.
+func (LastPivot) ChoosePivot(array []int, low, high int) int {
+	return high
+}
+
+// MedianOfThreePivot selects the median of the first, middle, and last elements as the pivot.
+type MedianOfThreePivot struct{}
+
+// ChoosePivot returns the index of the median of the first, middle, and last elements.
+func (MedianOfThreePivot) ChoosePivot(array []int, low, high int) int {
+	return medianOfThree(array, low, (low+high)/2, high)
+}
+
+// RandomPivot selects a uniformly random element of the subarray as the pivot.
+type RandomPivot struct {
+	rng *rand.Rand
+}
+
+// NewRandomPivot creates a RandomPivot with its own random source, so runs with the same seed are reproducible.
+func NewRandomPivot(seed int64) *RandomPivot {
+	return &RandomPivot{rng: rand.New(rand.NewSource(seed))}
+}
+
+// ChoosePivot returns the index of a random element.
+func (rp *RandomPivot) ChoosePivot(array []int, low, high int) int {
+	return low + rp.rng.Intn(high-low+1)
+}
+
+// NintherPivot selects Tukey's ninther: the median of the medians of three evenly spaced triples.
+// Subarrays with fewer than nine elements fall back to the median-of-three.
+type NintherPivot struct{}
+
+// ChoosePivot returns the index of the ninther.
+func (NintherPivot) ChoosePivot(array []int, low, high int) int {
+	n := high - low + 1
+	if n < 9 {
+		return medianOfThree(array, low, (low+high)/2, high)
 	}
+	step := n / 8
+	mid := (low + high) / 2
+	first := medianOfThree(array, low, low+step, low+2*step)
+	middle := medianOfThree(array, mid-step, mid, mid+step)
+	last := medianOfThree(array, high-2*step, high-step, high)
+	return medianOfThree(array, first, middle, last)
 }
 
-// PartitionFirst partitions the array using the first element as the pivot.
-// It moves elements smaller than the pivot to the left and larger to the right.
-func (qs *QuickSort) PartitionFirst(low, high int) int {
-	pivot := qs.array[low] // Choose the first element as the pivot.
-	i := low + 1           // Start pointer for the greater element.
+// medianOfThree returns whichever of the indices i, j and k holds the median value.
+// Candidates are ordered by value with ties kept in argument order, as in the original median-of-three rule.
+func medianOfThree(array []int, i, j, k int) int {
+	// Create a list of pivot candidates with their values and indices.
+	pivotCandidates := []struct {
+		value int
+		index int
+	}{
+		{array[i], i},
+		{array[j], j},
+		{array[k], k},
+	}
 
-	// Loop through the subarray and rearrange elements around the pivot.
-	for j := low + 1; j <= high; j++ {
-		if qs.array[j] < pivot {
-			qs.array[i], qs.array[j] = qs.array[j], qs.array[i]
-			i++
+	// Sort the pivot candidates by value to determine the median.
+	for a := 0; a < len(pivotCandidates)-1; a++ {
+		for b := a + 1; b < len(pivotCandidates); b++ {
+			if pivotCandidates[a].value > pivotCandidates[b].value {
+				pivotCandidates[a], pivotCandidates[b] = pivotCandidates[b], pivotCandidates[a]
+			}
 		}
 	}
-	qs.array[low], qs.array[i-1] = qs.array[i-1], qs.array[low] // Place the pivot in the correct position.
-	qs.comparisons += high - low                                // Count comparisons for the current partition.
-	return i - 1                                                // Return the pivot's final position.
+	return pivotCandidates[1].index
+}
+
+// Sort sorts the array with quicksort, choosing every pivot with the given strategy.
+func (qs *QuickSort) Sort(strategy PivotStrategy) {
+	qs.quicksort(0, len(qs.array)-1, strategy)
+}
+
+// SortThreeWay sorts the array with quicksort using a three-way (Dutch national flag) partition,
+// which groups all elements equal to the pivot and skips them in the recursion.
+// It is much faster than Sort on inputs with many duplicates.
+func (qs *QuickSort) SortThreeWay(strategy PivotStrategy) {
+	qs.quicksortThreeWay(0, len(qs.array)-1, strategy)
+}
+
+// QuickSortFirst sorts array[low..high] using the first element as the pivot.
+//
+// Deprecated: Use Sort(FirstPivot{}) instead.
+func (qs *QuickSort) QuickSortFirst(low, high int) {
+	qs.quicksort(low, high, FirstPivot{})
 }
 
-// QuickSortLast sorts the array using the last element as the pivot.
-// It recursively divides the array around the chosen pivot.
+// PartitionFirst partitions array[low..high] around its first element and returns the pivot's final index.
+//
+// Deprecated: Use Sort(FirstPivot{}) instead.
+func (qs *QuickSort) PartitionFirst(low, high int) int {
+	return qs.partition(low, high)
+}
+
+// QuickSortLast sorts array[low..high] using the last element as the pivot.
+//
+// Deprecated: Use Sort(LastPivot{}) instead.
 func (qs *QuickSort) QuickSortLast(low, high int) {
-	if low < high {
-		pivotIndex := qs.PartitionLast(low, high)
-		qs.QuickSortLast(low, pivotIndex-1)  // Sort elements before the pivot.
-		qs.QuickSortLast(pivotIndex+1, high) // Sort elements after the pivot.
-	}
+	qs.quicksort(low, high, LastPivot{})
 }
 
-// PartitionLast partitions the array using the last element as the pivot.
-// It swaps the last element to the beginning, then uses the first element as the pivot.
+// PartitionLast partitions array[low..high] around its last element and returns the pivot's final index.
+//
+// Deprecated: Use Sort(LastPivot{}) instead.
 func (qs *QuickSort) PartitionLast(low, high int) int {
-	qs.array[low], qs.array[high] = qs.array[high], qs.array[low] // Move the last element to the beginning.
-	return qs.PartitionFirst(low, high)                           // Use PartitionFirst logic.
+	qs.array[low], qs.array[high] = qs.array[high], qs.array[low]
+	return qs.partition(low, high)
 }
 
-// QuickSortMedian sorts the array using the median of the first, middle, and last elements as the pivot.
-// It recursively divides the array around the chosen pivot.
+// QuickSortMedian sorts array[low..high] using the median-of-three as the pivot.
+//
+// Deprecated: Use Sort(MedianOfThreePivot{}) instead.
 func (qs *QuickSort) QuickSortMedian(low, high int) {
-	if low < high {
-		pivotIndex := qs.PartitionMedian(low, high)
-		qs.QuickSortMedian(low, pivotIndex-1)  // Sort elements before the pivot.
-		qs.QuickSortMedian(pivotIndex+1, high) // Sort elements after the pivot.
-	}
+	qs.quicksort(low, high, MedianOfThreePivot{})
 }
 
-// PartitionMedian partitions the array using the median-of-three as the pivot.
-// It finds the median of the first, middle, and last elements, then uses it as the pivot.
+// PartitionMedian partitions array[low..high] around the median of its first, middle and last elements
+// and returns the pivot's final index.
+//
+// Deprecated: Use Sort(MedianOfThreePivot{}) instead.
 func (qs *QuickSort) PartitionMedian(low, high int) int {
-	mid := (low + high) / 2 // Calculate the middle index.
+	pivotIndex := MedianOfThreePivot{}.ChoosePivot(qs.array, low, high)
+	qs.array[low], qs.array[pivotIndex] = qs.array[pivotIndex], qs.array[low]
+	return qs.partition(low, high)
+}
 
-	// Create a slice of pivot candidates and their positions.
-	pivotCandidates := []struct {
-		value int
-		index int
-	}{
-		{qs.array[low], low},
-		{qs.array[mid], mid},
-		{qs.array[high], high},
+// quicksort is a recursive method that sorts array[low..high] around pivots chosen by the strategy.
+// It partitions the array around the pivot, then recursively sorts the left and right partitions.
+func (qs *QuickSort) quicksort(low, high int, strategy PivotStrategy) {
+	if low < high {
+		// Move the chosen pivot to the beginning of the subarray.
+		pivotIndex := strategy.ChoosePivot(qs.array, low, high)
+		qs.array[low], qs.array[pivotIndex] = qs.array[pivotIndex], qs.array[low]
+		// Partition the array around the pivot and get the pivot index.
+		pivotIndex = qs.partition(low, high)
+		// Recursively sort the elements to the left of the pivot.
+		qs.quicksort(low, pivotIndex-1, strategy)
+		// Recursively sort the elements to the right of the pivot.
+		qs.quicksort(pivotIndex+1, high, strategy)
 	}
+}
 
-	// Sort the pivot candidates to find the median.
-	for i := 0; i < len(pivotCandidates)-1; i++ {
-		for j := i + 1; j < len(pivotCandidates); j++ {
-			if pivotCandidates[i].value > pivotCandidates[j].value {
-				pivotCandidates[i], pivotCandidates[j] = pivotCandidates[j], pivotCandidates[i]
-			}
+// partition partitions the array using the first element as the pivot.
+// It places elements smaller than the pivot to its left and larger elements to its right.
+// It returns the final index of the pivot after partitioning.
+func (qs *QuickSort) partition(low, high int) int {
+	pivot := qs.array[low] // The pivot has already been moved to the first position.
+	i := low + 1           // Initialize pointer for the greater element.
+
+	// Iterate over the elements and rearrange them based on the pivot value.
+	for j := low + 1; j <= high; j++ {
+		if qs.array[j] < pivot {
+			// Swap elements to bring smaller elements to the left.
+			qs.array[i], qs.array[j] = qs.array[j], qs.array[i]
+			i++
 		}
 	}
+	// Place the pivot in its correct sorted position.
+	qs.array[low], qs.array[i-1] = qs.array[i-1], qs.array[low]
+	// Update the comparison count with the number of elements in the current partition.
+	qs.comparisons += high - low
+	// Return the index of the pivot after partitioning.
+	return i - 1
+}
 
-	// Choose the median element as the pivot.
-	pivotIndex := pivotCandidates[1].index
-	qs.array[low], qs.array[pivotIndex] = qs.array[pivotIndex], qs.array[low] // Place pivot at the start.
-	return qs.PartitionFirst(low, high)                                       // Partition using the selected pivot.
+// quicksortThreeWay is a recursive method that sorts array[low..high] with three-way partitioning.
+func (qs *QuickSort) quicksortThreeWay(low, high int, strategy PivotStrategy) {
+	if low < high {
+		pivotIndex := strategy.ChoosePivot(qs.array, low, high)
+		lt, gt := qs.partitionThreeWay(low, high, pivotIndex)
+		// Elements in array[lt..gt] equal the pivot and are already in place.
+		qs.quicksortThreeWay(low, lt-1, strategy)
+		qs.quicksortThreeWay(gt+1, high, strategy)
+	}
+}
+
+// partitionThreeWay rearranges array[low..high] into elements less than, equal to and greater than
+// the pivot, and returns the bounds [lt, gt] of the equal block.
+// Every element is compared with the pivot once or twice, and each comparison is counted.
+func (qs *QuickSort) partitionThreeWay(low, high, pivotIndex int) (int, int) {
+	pivot := qs.array[pivotIndex]
+	lt, i, gt := low, low, high
+
+	// Invariant: array[low..lt-1] < pivot, array[lt..i-1] == pivot, array[gt+1..high] > pivot.
+	for i <= gt {
+		qs.comparisons++
+		if qs.array[i] < pivot {
+			qs.array[lt], qs.array[i] = qs.array[i], qs.array[lt]
+			lt++
+			i++
+			continue
+		}
+		qs.comparisons++
+		if qs.array[i] > pivot {
+			qs.array[i], qs.array[gt] = qs.array[gt], qs.array[i]
+			gt--
+		} else {
+			i++
+		}
+	}
+	return lt, gt
 }
 
 // GetComparisons returns the total number of comparisons made during sorting.
@@ -143,18 +281,27 @@ func main() {
 		return
 	}
 
-	// Sort using the first element as the pivot and print the number of comparisons.
-	qsFirst := NewQuickSort(array)
-	qsFirst.QuickSortFirst(0, len(array)-1)
-	fmt.Printf("Total comparisons with first element as pivot: %d\n", qsFirst.GetComparisons())
-
-	// Sort using the last element as the pivot and print the number of comparisons.
-	qsLast := NewQuickSort(array)
-	qsLast.QuickSortLast(0, len(array)-1)
-	fmt.Printf("Total comparisons with last element as pivot: %d\n", qsLast.GetComparisons())
+	// Sort with each pivot strategy and print the number of comparisons.
+	strategies := []struct {
+		name     string
+		strategy PivotStrategy
+	}{
+		{"first element", FirstPivot{}},
+		{"last element", LastPivot{}},
+		{"median-of-three", MedianOfThreePivot{}},
+		{"random element", NewRandomPivot(1)},
+		{"Tukey's ninther", NintherPivot{}},
+	}
+	for _, s := range strategies {
+		qs := NewQuickSort(array)
+		qs.Sort(s.strategy)
+		fmt.Printf("Total comparisons with %s as pivot: %d\n", s.name, qs.GetComparisons())
+	}
 
-	// Sort using the median-of-three as the pivot and print the number of comparisons.
-	qsMedian := NewQuickSort(array)
-	qsMedian.QuickSortMedian(0, len(array)-1)
-	fmt.Printf("Total comparisons with median-of-three as pivot: %d\n", qsMedian.GetComparisons())
+	// The same strategies with three-way partitioning.
+	for _, s := range strategies {
+		qs := NewQuickSort(array)
+		qs.SortThreeWay(s.strategy)
+		fmt.Printf("Total comparisons with %s as pivot (three-way partition): %d\n", s.name, qs.GetComparisons())
+	}
 }
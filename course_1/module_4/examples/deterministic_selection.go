@@ -1,30 +1,38 @@
 package main
 
 import (
+	"cmp"
 	"errors"
+	"flag"
 	"fmt"
+	"math/rand"
+	"slices"
 	"sort"
+	"time"
 )
 
 // DeterministicSelection struct encapsulates the array and provides methods for the selection algorithm.
-type DeterministicSelection struct {
-	array []int
+// The `comparisons` counter tracks the number of element comparisons made by all selections so far.
+type DeterministicSelection[T cmp.Ordered] struct {
+	array       []T
+	comparisons int
 }
 
 // NewDeterministicSelection creates a new instance of DeterministicSelection.
-func NewDeterministicSelection(inputArray []int) *DeterministicSelection {
-	return &DeterministicSelection{array: inputArray}
+func NewDeterministicSelection[T cmp.Ordered](inputArray []T) *DeterministicSelection[T] {
+	return &DeterministicSelection[T]{array: inputArray}
 }
 
 // partition rearranges the array such that elements less than the pivot are on the left
 // and elements greater than the pivot are on the right.
-func (ds *DeterministicSelection) partition(left, right, pivotIndex int) int {
+func (ds *DeterministicSelection[T]) partition(left, right, pivotIndex int) int {
 	pivotValue := ds.array[pivotIndex]
 	// Move pivot to the end.
 	ds.array[pivotIndex], ds.array[right] = ds.array[right], ds.array[pivotIndex]
 	storeIndex := left
 
 	// Rearrange elements based on the pivot value.
+	ds.comparisons += right - left
 	for i := left; i < right; i++ {
 		if ds.array[i] < pivotValue {
 			ds.array[i], ds.array[storeIndex] = ds.array[storeIndex], ds.array[i]
@@ -38,7 +46,9 @@ func (ds *DeterministicSelection) partition(left, right, pivotIndex int) int {
 }
 
 // medianOfMedians selects a pivot deterministically using the "median of medians" strategy.
-func (ds *DeterministicSelection) medianOfMedians(left, right int) int {
+// It returns the pivot's index in ds.array, which is where the median of the group medians was left by
+// medianOfSmallGroup, not its offset among the medians.
+func (ds *DeterministicSelection[T]) medianOfMedians(left, right int) int {
 	n := right - left + 1
 
 	// If the subarray has 5 or fewer elements, return the median directly.
@@ -46,8 +56,9 @@ func (ds *DeterministicSelection) medianOfMedians(left, right int) int {
 		return ds.medianOfSmallGroup(left, right)
 	}
 
-	// Divide array into groups of 5 and find their medians.
-	medians := make([]int, 0, (n+4)/5) // +4 to ensure the division rounds up.
+	// Divide array into groups of 5 and find their medians, remembering where each median sits.
+	medians := make([]T, 0, (n+4)/5) // +4 to ensure the division rounds up.
+	medianIndices := make([]int, 0, (n+4)/5)
 	for i := left; i <= right; i += 5 {
 		subRight := i + 4
 		if subRight > right {
@@ -55,23 +66,35 @@ func (ds *DeterministicSelection) medianOfMedians(left, right int) int {
 		}
 		median := ds.medianOfSmallGroup(i, subRight)
 		medians = append(medians, ds.array[median])
+		medianIndices = append(medianIndices, median)
 	}
 
-	// Recursively find the median of medians.
-	newDS := NewDeterministicSelection(medians)
-	return left + newDS.deterministicSelect(0, len(medians)-1, len(medians)/2)
+	// Recursively find the median of medians and return its position in the original array.
+	newDS := NewDeterministicSelection(slices.Clone(medians))
+	medianValue := newDS.array[newDS.deterministicSelect(0, len(medians)-1, len(medians)/2)]
+	ds.comparisons += newDS.comparisons
+	for i, value := range medians {
+		ds.comparisons++
+		if value == medianValue {
+			return medianIndices[i]
+		}
+	}
+	return medianIndices[0]
 }
 
 // medianOfSmallGroup finds the median of a small group of up to 5 elements.
-func (ds *DeterministicSelection) medianOfSmallGroup(left, right int) int {
+func (ds *DeterministicSelection[T]) medianOfSmallGroup(left, right int) int {
 	// Sort the group and find the median index.
 	subArray := ds.array[left : right+1]
-	sort.Ints(subArray)
+	slices.SortFunc(subArray, func(a, b T) int {
+		ds.comparisons++
+		return cmp.Compare(a, b)
+	})
 	return left + (right-left)/2
 }
 
 // deterministicSelect recursively finds the k-th smallest element.
-func (ds *DeterministicSelection) deterministicSelect(left, right, k int) int {
+func (ds *DeterministicSelection[T]) deterministicSelect(left, right, k int) int {
 	// Base case: if the array has only one element.
 	if left == right {
 		return left
@@ -98,16 +121,162 @@ func (ds *DeterministicSelection) deterministicSelect(left, right, k int) int {
 	}
 }
 
+// multiSelect places the elements of rank positions[i]+1 at their sorted positions in one recursive pass.
+// positions are sorted, distinct, 0-based and all within [left, right].
+func (ds *DeterministicSelection[T]) multiSelect(left, right int, positions []int) {
+	if len(positions) == 0 || left >= right {
+		return
+	}
+
+	// Partition once around a deterministic pivot and send each target to the side that contains it.
+	pivotIndex := ds.partition(left, right, ds.medianOfMedians(left, right))
+	split := sort.SearchInts(positions, pivotIndex)
+	ds.multiSelect(left, pivotIndex-1, positions[:split])
+	if split < len(positions) && positions[split] == pivotIndex {
+		split++
+	}
+	ds.multiSelect(pivotIndex+1, right, positions[split:])
+}
+
 // Select is the public method to find the k-th smallest element in the array.
-func (ds *DeterministicSelection) Select(k int) (int, error) {
+func (ds *DeterministicSelection[T]) Select(k int) (T, error) {
 	if k < 1 || k > len(ds.array) {
-		return 0, errors.New("k is out of bounds of the array")
+		var zero T
+		return zero, errors.New("k is out of bounds of the array")
 	}
 	index := ds.deterministicSelect(0, len(ds.array)-1, k)
 	return ds.array[index], nil
 }
 
+// SelectMany returns the k-th smallest element for every k in ks (1-based), in the order requested.
+// All order statistics are found in a single recursive pass over one copy of the array.
+func (ds *DeterministicSelection[T]) SelectMany(ks []int) ([]T, error) {
+	positions, err := selectionPositions(ks, len(ds.array))
+	if err != nil {
+		return nil, err
+	}
+
+	working := NewDeterministicSelection(slices.Clone(ds.array))
+	working.multiSelect(0, len(working.array)-1, positions)
+	ds.comparisons += working.comparisons
+
+	result := make([]T, len(ks))
+	for i, k := range ks {
+		result[i] = working.array[k-1]
+	}
+	return result, nil
+}
+
+// TopK returns the k largest elements in decreasing order.
+func (ds *DeterministicSelection[T]) TopK(k int) ([]T, error) {
+	if k < 0 || k > len(ds.array) {
+		return nil, errors.New("k is out of bounds of the array")
+	}
+	if k == 0 {
+		return []T{}, nil
+	}
+
+	// After selecting position n-k, everything to its right is at least as large.
+	working := NewDeterministicSelection(slices.Clone(ds.array))
+	n := len(working.array)
+	working.multiSelect(0, n-1, []int{n - k})
+	ds.comparisons += working.comparisons
+
+	top := working.array[n-k:]
+	slices.SortFunc(top, func(a, b T) int { return cmp.Compare(b, a) })
+	return top, nil
+}
+
+// GetComparisons returns the total number of element comparisons made so far.
+func (ds *DeterministicSelection[T]) GetComparisons() int {
+	return ds.comparisons
+}
+
+// selectionPositions validates 1-based ranks and returns them as sorted, distinct 0-based positions.
+func selectionPositions(ks []int, n int) ([]int, error) {
+	positions := make([]int, 0, len(ks))
+	for _, k := range ks {
+		if k < 1 || k > n {
+			return nil, fmt.Errorf("k = %d is out of bounds of the array", k)
+		}
+		positions = append(positions, k-1)
+	}
+	slices.Sort(positions)
+	return slices.Compact(positions), nil
+}
+
+// benchmarkInput is a named input for the benchmarks.
+type benchmarkInput struct {
+	name   string
+	values []int
+}
+
+// benchmarkInputs returns random, sorted, median-of-3-killer and organ pipe inputs of size n, which must be
+// divisible by 4. The same seed and constructions are used by every selection example, so their results
+// can be compared line by line.
+func benchmarkInputs(n int) []benchmarkInput {
+	random := rand.New(rand.NewSource(1)).Perm(n)
+	sorted := make([]int, n)
+	organPipe := make([]int, n)
+	for i := range random {
+		random[i]++
+		sorted[i] = i + 1
+		organPipe[i] = min(i, n-1-i) + 1
+	}
+	return []benchmarkInput{
+		{"random", random},
+		{"sorted", sorted},
+		{"median-of-3 killer", medianOfThreeKiller(n)},
+		{"organ pipe", organPipe},
+	}
+}
+
+// medianOfThreeKiller returns Musser's permutation of 1..n, on which quicksort with the median-of-three
+// pivot rule only shrinks each subarray by two elements. n must be divisible by 4: the odd values are
+// interleaved pairwise in the first half, which needs n/2 to be even. None of the selection examples
+// picks its pivot by median-of-three, so for them it is a structured input rather than a worst case;
+// the organ pipe, whose middle block holds the largest values, is the one that stresses Floyd–Rivest.
+func medianOfThreeKiller(n int) []int {
+	k := n / 2
+	values := make([]int, n)
+	for i := 1; i <= k; i++ {
+		if i%2 == 1 {
+			values[i-1] = i
+			values[i] = k + i
+		}
+		values[k+i-1] = 2 * i
+	}
+	return values
+}
+
+// benchmarkDuration is how long each benchmark repeats a selection to average its running time.
+const benchmarkDuration = 200 * time.Millisecond
+
+// runBenchmarks selects the median of every benchmark input and reports the average running time and the
+// comparisons. Each run works on a fresh copy of the input.
+func runBenchmarks() {
+	for _, size := range []int{10000, 1000000} {
+		fmt.Printf("%s, n = %d\n", "DeterministicSelection", size)
+		for _, input := range benchmarkInputs(size) {
+			comparisons, runs := 0, 0
+			start := time.Now()
+			for runs == 0 || time.Since(start) < benchmarkDuration {
+				selector := NewDeterministicSelection(slices.Clone(input.values))
+				selector.Select(size / 2)
+				comparisons = selector.GetComparisons()
+				runs++
+			}
+			perRun := time.Since(start) / time.Duration(runs)
+			fmt.Printf("  %-20s %14d ns/op %12d comparisons (%.2f n)\n",
+				input.name, perRun.Nanoseconds(), comparisons, float64(comparisons)/float64(size))
+		}
+	}
+}
+
 func main() {
+	bench := flag.Bool("bench", false, "benchmark median selection on random, sorted, median-of-3-killer and organ pipe inputs")
+	flag.Parse()
+
 	// Input array.
 	array := []int{10, 4, 5, 8, 6, 11, 26}
 	k := 3 // Desired rank (1-based).
@@ -124,4 +293,37 @@ func main() {
 
 	// Print the result.
 	fmt.Printf("The %d-th smallest element is: %d\n", k, result)
+	fmt.Println("Comparisons:", selector.GetComparisons())
+
+	// Several order statistics in one pass.
+	ranks := []int{1, 4, 7, 2}
+	values, err := selector.SelectMany(ranks)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("Order statistics %v: %v\n", ranks, values)
+
+	// The three largest elements.
+	top, err := selector.TopK(3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Top 3 elements:", top)
+
+	// Any ordered type works, e.g. strings.
+	words := NewDeterministicSelection([]string{"pear", "apple", "fig", "kiwi", "banana", "cherry", "date"})
+	median, _ := words.Select(4)
+	topWords, _ := words.TopK(2)
+	fmt.Printf("Median word: %s, top 2 words: %v\n", median, topWords)
+
+	// Out-of-range ranks are rejected.
+	if _, err := selector.SelectMany([]int{0, 3}); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	if *bench {
+		runBenchmarks()
+	}
 }
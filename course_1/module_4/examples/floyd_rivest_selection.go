@@ -0,0 +1,344 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// floydRivestSampleThreshold is the subarray length above which a sample is recursed on to narrow the range.
+const floydRivestSampleThreshold = 600
+
+// FloydRivestSelection struct encapsulates the array and provides methods for Floyd–Rivest selection.
+// The algorithm recursively selects from a small sample to find two values that bracket the k-th
+// smallest element with high probability, so a single partition pass leaves only a small range to
+// search. It needs n + min(k, n-k) + o(n) comparisons on average, close to the lower bound.
+// The sample is the block of elements around k, so the bound assumes the input is in random order;
+// structured inputs such as an organ pipe, whose middle block holds only the largest values, can cost
+// several times more comparisons.
+// The `comparisons` counter tracks the number of element comparisons made by all selections so far.
+type FloydRivestSelection[T cmp.Ordered] struct {
+	array       []T
+	comparisons int
+}
+
+// NewFloydRivestSelection creates a new instance of FloydRivestSelection.
+func NewFloydRivestSelection[T cmp.Ordered](array []T) (*FloydRivestSelection[T], error) {
+	if len(array) == 0 {
+		return nil, errors.New("input array must not be empty")
+	}
+	return &FloydRivestSelection[T]{array: append([]T{}, array...)}, nil
+}
+
+// less compares two elements and counts the comparison.
+func (fr *FloydRivestSelection[T]) less(a, b T) bool {
+	fr.comparisons++
+	return a < b
+}
+
+// floydRivest rearranges array[left..right] so that the element at index k is the one that would be
+// there if the range were sorted, with smaller or equal elements before it and larger or equal after it.
+func (fr *FloydRivestSelection[T]) floydRivest(left, right, k int) {
+	for right > left {
+		// On large ranges, first select from a sample around k to bring good pivots near k.
+		if right-left > floydRivestSampleThreshold {
+			n := float64(right - left + 1)
+			i := float64(k - left + 1)
+			z := math.Log(n)
+			s := 0.5 * math.Exp(2*z/3)
+			sd := 0.5 * math.Sqrt(z*s*(n-s)/n)
+			if i < n/2 {
+				sd = -sd
+			}
+			newLeft := max(left, int(float64(k)-i*s/n+sd))
+			newRight := min(right, int(float64(k)+(n-i)*s/n+sd))
+			fr.floydRivest(newLeft, newRight, k)
+		}
+
+		// Partition around t = array[k], using the ends as sentinels for the inner scans.
+		t := fr.array[k]
+		i, j := left, right
+		fr.array[left], fr.array[k] = fr.array[k], fr.array[left]
+		if fr.less(t, fr.array[right]) {
+			fr.array[right], fr.array[left] = fr.array[left], fr.array[right]
+		}
+		for i < j {
+			fr.array[i], fr.array[j] = fr.array[j], fr.array[i]
+			i++
+			j--
+			for fr.less(fr.array[i], t) {
+				i++
+			}
+			for fr.less(t, fr.array[j]) {
+				j--
+			}
+		}
+
+		// Move the pivot to its final position j.
+		fr.comparisons++
+		if fr.array[left] == t {
+			fr.array[left], fr.array[j] = fr.array[j], fr.array[left]
+		} else {
+			j++
+			fr.array[j], fr.array[right] = fr.array[right], fr.array[j]
+		}
+
+		// Continue on the side that contains k.
+		if j <= k {
+			left = j + 1
+		}
+		if k <= j {
+			right = j - 1
+		}
+	}
+}
+
+// Select finds the k-th smallest element in the array.
+func (fr *FloydRivestSelection[T]) Select(k int) (T, error) {
+	if k < 1 || k > len(fr.array) {
+		var zero T
+		return zero, errors.New("k is out of bounds of the array")
+	}
+	fr.floydRivest(0, len(fr.array)-1, k-1)
+	return fr.array[k-1], nil
+}
+
+// GetComparisons returns the total number of element comparisons made so far.
+func (fr *FloydRivestSelection[T]) GetComparisons() int {
+	return fr.comparisons
+}
+
+// WeightedValue is a value together with its non-negative weight.
+type WeightedValue[T cmp.Ordered] struct {
+	Value  T
+	Weight float64
+}
+
+// WeightedMedian returns the lower weighted median: the smallest value v such that the values less than
+// or equal to v carry at least half of the total weight. For points on a line, it is the location of a
+// facility that minimizes the weighted sum of distances to all points.
+// Every step pivots on the median value of the remaining range, found with Floyd–Rivest selection,
+// so the range at least halves and the total running time is linear.
+func WeightedMedian[T cmp.Ordered](items []WeightedValue[T]) (T, error) {
+	var zero T
+	if len(items) == 0 {
+		return zero, errors.New("at least one weighted value is required")
+	}
+	total := 0.0
+	for i, item := range items {
+		if item.Weight < 0 || math.IsNaN(item.Weight) || math.IsInf(item.Weight, 0) {
+			return zero, fmt.Errorf("weight %v of item %d is not a finite non-negative number", item.Weight, i)
+		}
+		total += item.Weight
+	}
+	if total == 0 {
+		return zero, errors.New("total weight must be positive")
+	}
+
+	work := slices.Clone(items)
+	half := total / 2
+	below := 0.0 // Weight of the items already known to lie left of the range.
+	left, right := 0, len(work)-1
+	for {
+		values := make([]T, 0, right-left+1)
+		for _, item := range work[left : right+1] {
+			values = append(values, item.Value)
+		}
+		selector := &FloydRivestSelection[T]{array: values}
+		pivot, _ := selector.Select((len(values) + 1) / 2)
+
+		lt, gt, lessWeight, equalWeight := partitionWeighted(work, left, right, pivot)
+		switch {
+		case below+lessWeight >= half:
+			right = lt - 1
+		case below+lessWeight+equalWeight >= half:
+			return pivot, nil
+		default:
+			below += lessWeight + equalWeight
+			left = gt + 1
+		}
+	}
+}
+
+// partitionWeighted rearranges items[left..right] into values less than, equal to and greater than
+// the pivot, and returns the bounds [lt, gt] of the equal block with the weights of the first two parts.
+func partitionWeighted[T cmp.Ordered](items []WeightedValue[T], left, right int, pivot T) (int, int, float64, float64) {
+	lt, i, gt := left, left, right
+	lessWeight, equalWeight := 0.0, 0.0
+	for i <= gt {
+		switch {
+		case items[i].Value < pivot:
+			lessWeight += items[i].Weight
+			items[lt], items[i] = items[i], items[lt]
+			lt++
+			i++
+		case items[i].Value > pivot:
+			items[i], items[gt] = items[gt], items[i]
+			gt--
+		default:
+			equalWeight += items[i].Weight
+			i++
+		}
+	}
+	return lt, gt, lessWeight, equalWeight
+}
+
+// weightedMedianBySorting finds the lower weighted median by sorting, for checking WeightedMedian.
+func weightedMedianBySorting[T cmp.Ordered](items []WeightedValue[T]) T {
+	sorted := slices.Clone(items)
+	slices.SortFunc(sorted, func(a, b WeightedValue[T]) int { return cmp.Compare(a.Value, b.Value) })
+	total := 0.0
+	for _, item := range sorted {
+		total += item.Weight
+	}
+	cumulative := 0.0
+	for _, item := range sorted {
+		cumulative += item.Weight
+		if cumulative >= total/2 {
+			return item.Value
+		}
+	}
+	return sorted[len(sorted)-1].Value
+}
+
+// benchmarkInput is a named input for the benchmarks.
+type benchmarkInput struct {
+	name   string
+	values []int
+}
+
+// benchmarkInputs returns random, sorted, median-of-3-killer and organ pipe inputs of size n, which must be
+// divisible by 4. The same seed and constructions are used by every selection example, so their results
+// can be compared line by line.
+func benchmarkInputs(n int) []benchmarkInput {
+	random := rand.New(rand.NewSource(1)).Perm(n)
+	sorted := make([]int, n)
+	organPipe := make([]int, n)
+	for i := range random {
+		random[i]++
+		sorted[i] = i + 1
+		organPipe[i] = min(i, n-1-i) + 1
+	}
+	return []benchmarkInput{
+		{"random", random},
+		{"sorted", sorted},
+		{"median-of-3 killer", medianOfThreeKiller(n)},
+		{"organ pipe", organPipe},
+	}
+}
+
+// medianOfThreeKiller returns Musser's permutation of 1..n, on which quicksort with the median-of-three
+// pivot rule only shrinks each subarray by two elements. n must be divisible by 4: the odd values are
+// interleaved pairwise in the first half, which needs n/2 to be even. None of the selection examples
+// picks its pivot by median-of-three, so for them it is a structured input rather than a worst case;
+// the organ pipe, whose middle block holds the largest values, is the one that stresses Floyd–Rivest.
+func medianOfThreeKiller(n int) []int {
+	k := n / 2
+	values := make([]int, n)
+	for i := 1; i <= k; i++ {
+		if i%2 == 1 {
+			values[i-1] = i
+			values[i] = k + i
+		}
+		values[k+i-1] = 2 * i
+	}
+	return values
+}
+
+// benchmarkDuration is how long each benchmark repeats a selection to average its running time.
+const benchmarkDuration = 200 * time.Millisecond
+
+// runBenchmarks selects the median of every benchmark input and reports the average running time and the
+// comparisons. Each run works on a fresh copy of the input.
+func runBenchmarks() {
+	for _, size := range []int{10000, 1000000} {
+		fmt.Printf("%s, n = %d\n", "FloydRivestSelection", size)
+		for _, input := range benchmarkInputs(size) {
+			comparisons, runs := 0, 0
+			start := time.Now()
+			for runs == 0 || time.Since(start) < benchmarkDuration {
+				selector, _ := NewFloydRivestSelection(input.values)
+				selector.Select(size / 2)
+				comparisons = selector.GetComparisons()
+				runs++
+			}
+			perRun := time.Since(start) / time.Duration(runs)
+			fmt.Printf("  %-20s %14d ns/op %12d comparisons (%.2f n)\n",
+				input.name, perRun.Nanoseconds(), comparisons, float64(comparisons)/float64(size))
+		}
+	}
+}
+
+func main() {
+	bench := flag.Bool("bench", false, "benchmark median selection on random, sorted, median-of-3-killer and organ pipe inputs")
+	flag.Parse()
+
+	array := []int{10, 4, 5, 8, 6, 11, 26}
+	k := 3
+
+	selector, err := NewFloydRivestSelection(array)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	result, err := selector.Select(k)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("The %d-th smallest element is: %d\n", k, result)
+	fmt.Println("Comparisons:", selector.GetComparisons())
+
+	// Check every rank of random inputs with many duplicates against sorting; the large size exercises sampling.
+	matches := true
+	for _, size := range []int{1, 2, 7, 100, 5000} {
+		values := make([]int, size)
+		for i := range values {
+			values[i] = rand.Intn(size/2 + 1)
+		}
+		sorted := slices.Clone(values)
+		slices.Sort(sorted)
+		for _, rank := range []int{1, (size + 1) / 2, size, rand.Intn(size) + 1} {
+			selector, _ := NewFloydRivestSelection(values)
+			value, err := selector.Select(rank)
+			matches = matches && err == nil && value == sorted[rank-1]
+		}
+	}
+	fmt.Println("Floyd–Rivest matches sorting:", matches)
+
+	// Facility location: houses along a street, weighted by the number of residents.
+	houses := []WeightedValue[float64]{{1.0, 4}, {2.5, 1}, {4.0, 1}, {7.5, 2}, {9.0, 1}}
+	location, err := WeightedMedian(houses)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("Best facility location: %.1f\n", location)
+
+	// Check the weighted median against sorting; integer weights keep the sums exact.
+	matches = true
+	for _, size := range []int{1, 2, 9, 1000, 20000} {
+		items := make([]WeightedValue[int], size)
+		for i := range items {
+			items[i] = WeightedValue[int]{Value: rand.Intn(size), Weight: float64(rand.Intn(10))}
+		}
+		items[0].Weight++ // Keep the total weight positive.
+		median, err := WeightedMedian(items)
+		matches = matches && err == nil && median == weightedMedianBySorting(items)
+	}
+	fmt.Println("Weighted median matches sorting:", matches)
+
+	// Invalid weights are rejected.
+	if _, err := WeightedMedian([]WeightedValue[int]{{1, 1}, {2, -1}}); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	if *bench {
+		runBenchmarks()
+	}
+}
@@ -1,33 +1,40 @@
 package main
 
 import (
+	"cmp"
 	"errors"
+	"flag"
 	"fmt"
 	"math/rand"
+	"slices"
+	"sort"
 	"time"
 )
 
 // RandomizedSelection struct encapsulates the array and its methods.
-type RandomizedSelection struct {
-	array []int
+// The `comparisons` counter tracks the number of element comparisons made by all selections so far.
+type RandomizedSelection[T cmp.Ordered] struct {
+	array       []T
+	comparisons int
 }
 
 // NewRandomizedSelection creates a new instance of RandomizedSelection.
-func NewRandomizedSelection(array []int) (*RandomizedSelection, error) {
+func NewRandomizedSelection[T cmp.Ordered](array []T) (*RandomizedSelection[T], error) {
 	if len(array) == 0 {
 		return nil, errors.New("input array must not be empty")
 	}
-	return &RandomizedSelection{array: append([]int{}, array...)}, nil
+	return &RandomizedSelection[T]{array: append([]T{}, array...)}, nil
 }
 
 // partition partitions the array around the pivot element.
-func (rs *RandomizedSelection) partition(left, right, pivotIndex int) int {
+func (rs *RandomizedSelection[T]) partition(left, right, pivotIndex int) int {
 	pivotValue := rs.array[pivotIndex]
 	// Move pivot to the end.
 	rs.array[pivotIndex], rs.array[right] = rs.array[right], rs.array[pivotIndex]
 	storeIndex := left
 
 	// Rearrange elements around the pivot.
+	rs.comparisons += right - left
 	for i := left; i < right; i++ {
 		if rs.array[i] < pivotValue {
 			rs.array[i], rs.array[storeIndex] = rs.array[storeIndex], rs.array[i]
@@ -41,7 +48,7 @@ func (rs *RandomizedSelection) partition(left, right, pivotIndex int) int {
 }
 
 // randomizedSelect recursively finds the k-th smallest element.
-func (rs *RandomizedSelection) randomizedSelect(left, right, k int) int {
+func (rs *RandomizedSelection[T]) randomizedSelect(left, right, k int) T {
 	if left == right {
 		return rs.array[left] // Base case: only one element.
 	}
@@ -64,15 +71,158 @@ func (rs *RandomizedSelection) randomizedSelect(left, right, k int) int {
 	}
 }
 
+// multiSelect places the elements of rank positions[i]+1 at their sorted positions in one recursive pass.
+// positions are sorted, distinct, 0-based and all within [left, right].
+func (rs *RandomizedSelection[T]) multiSelect(left, right int, positions []int) {
+	if len(positions) == 0 || left >= right {
+		return
+	}
+
+	// Partition once around a random pivot and send each target to the side that contains it.
+	pivotIndex := rs.partition(left, right, rand.Intn(right-left+1)+left)
+	split := sort.SearchInts(positions, pivotIndex)
+	rs.multiSelect(left, pivotIndex-1, positions[:split])
+	if split < len(positions) && positions[split] == pivotIndex {
+		split++
+	}
+	rs.multiSelect(pivotIndex+1, right, positions[split:])
+}
+
 // Select finds the k-th smallest element in the array.
-func (rs *RandomizedSelection) Select(k int) (int, error) {
+func (rs *RandomizedSelection[T]) Select(k int) (T, error) {
 	if k < 1 || k > len(rs.array) {
-		return 0, errors.New("k is out of bounds of the array")
+		var zero T
+		return zero, errors.New("k is out of bounds of the array")
 	}
 	return rs.randomizedSelect(0, len(rs.array)-1, k), nil
 }
 
+// SelectMany returns the k-th smallest element for every k in ks (1-based), in the order requested.
+// All order statistics are found in a single recursive pass; the work array is partitioned in place,
+// so later calls benefit from the order established by earlier ones.
+func (rs *RandomizedSelection[T]) SelectMany(ks []int) ([]T, error) {
+	positions, err := selectionPositions(ks, len(rs.array))
+	if err != nil {
+		return nil, err
+	}
+
+	rs.multiSelect(0, len(rs.array)-1, positions)
+
+	result := make([]T, len(ks))
+	for i, k := range ks {
+		result[i] = rs.array[k-1]
+	}
+	return result, nil
+}
+
+// TopK returns the k largest elements in decreasing order.
+func (rs *RandomizedSelection[T]) TopK(k int) ([]T, error) {
+	if k < 0 || k > len(rs.array) {
+		return nil, errors.New("k is out of bounds of the array")
+	}
+	if k == 0 {
+		return []T{}, nil
+	}
+
+	// After selecting position n-k, everything to its right is at least as large.
+	n := len(rs.array)
+	rs.multiSelect(0, n-1, []int{n - k})
+
+	top := slices.Clone(rs.array[n-k:])
+	slices.SortFunc(top, func(a, b T) int { return cmp.Compare(b, a) })
+	return top, nil
+}
+
+// GetComparisons returns the total number of element comparisons made so far.
+func (rs *RandomizedSelection[T]) GetComparisons() int {
+	return rs.comparisons
+}
+
+// selectionPositions validates 1-based ranks and returns them as sorted, distinct 0-based positions.
+func selectionPositions(ks []int, n int) ([]int, error) {
+	positions := make([]int, 0, len(ks))
+	for _, k := range ks {
+		if k < 1 || k > n {
+			return nil, fmt.Errorf("k = %d is out of bounds of the array", k)
+		}
+		positions = append(positions, k-1)
+	}
+	slices.Sort(positions)
+	return slices.Compact(positions), nil
+}
+
+// benchmarkInput is a named input for the benchmarks.
+type benchmarkInput struct {
+	name   string
+	values []int
+}
+
+// benchmarkInputs returns random, sorted, median-of-3-killer and organ pipe inputs of size n, which must be
+// divisible by 4. The same seed and constructions are used by every selection example, so their results
+// can be compared line by line.
+func benchmarkInputs(n int) []benchmarkInput {
+	random := rand.New(rand.NewSource(1)).Perm(n)
+	sorted := make([]int, n)
+	organPipe := make([]int, n)
+	for i := range random {
+		random[i]++
+		sorted[i] = i + 1
+		organPipe[i] = min(i, n-1-i) + 1
+	}
+	return []benchmarkInput{
+		{"random", random},
+		{"sorted", sorted},
+		{"median-of-3 killer", medianOfThreeKiller(n)},
+		{"organ pipe", organPipe},
+	}
+}
+
+// medianOfThreeKiller returns Musser's permutation of 1..n, on which quicksort with the median-of-three
+// pivot rule only shrinks each subarray by two elements. n must be divisible by 4: the odd values are
+// interleaved pairwise in the first half, which needs n/2 to be even. None of the selection examples
+// picks its pivot by median-of-three, so for them it is a structured input rather than a worst case;
+// the organ pipe, whose middle block holds the largest values, is the one that stresses Floyd–Rivest.
+func medianOfThreeKiller(n int) []int {
+	k := n / 2
+	values := make([]int, n)
+	for i := 1; i <= k; i++ {
+		if i%2 == 1 {
+			values[i-1] = i
+			values[i] = k + i
+		}
+		values[k+i-1] = 2 * i
+	}
+	return values
+}
+
+// benchmarkDuration is how long each benchmark repeats a selection to average its running time.
+const benchmarkDuration = 200 * time.Millisecond
+
+// runBenchmarks selects the median of every benchmark input and reports the average running time and the
+// comparisons. Each run works on a fresh copy of the input.
+func runBenchmarks() {
+	for _, size := range []int{10000, 1000000} {
+		fmt.Printf("%s, n = %d\n", "RandomizedSelection", size)
+		for _, input := range benchmarkInputs(size) {
+			comparisons, runs := 0, 0
+			start := time.Now()
+			for runs == 0 || time.Since(start) < benchmarkDuration {
+				selector, _ := NewRandomizedSelection(input.values)
+				selector.Select(size / 2)
+				comparisons = selector.GetComparisons()
+				runs++
+			}
+			perRun := time.Since(start) / time.Duration(runs)
+			fmt.Printf("  %-20s %14d ns/op %12d comparisons (%.2f n)\n",
+				input.name, perRun.Nanoseconds(), comparisons, float64(comparisons)/float64(size))
+		}
+	}
+}
+
 func main() {
+	bench := flag.Bool("bench", false, "benchmark median selection on random, sorted, median-of-3-killer and organ pipe inputs")
+	flag.Parse()
+
 	// Initialize the random seed.
 	rand.Seed(time.Now().UnixNano())
 
@@ -93,4 +243,35 @@ func main() {
 		return
 	}
 	fmt.Printf("The %d-th smallest element is: %d\n", k, result)
+	fmt.Println("Comparisons:", selector.GetComparisons())
+
+	// Several order statistics in one pass.
+	ranks := []int{1, 4, 7, 2}
+	values, err := selector.SelectMany(ranks)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("Order statistics %v: %v\n", ranks, values)
+
+	// The three largest elements.
+	top, err := selector.TopK(3)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Println("Top 3 elements:", top)
+
+	// Any ordered type works, e.g. floats.
+	prices, err := NewRandomizedSelection([]float64{9.99, 1.5, 3.25, 7.0, 2.75})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	cheapest, _ := prices.SelectMany([]int{1, 2})
+	fmt.Println("Two cheapest prices:", cheapest)
+
+	if *bench {
+		runBenchmarks()
+	}
 }
@@ -2,9 +2,15 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
+	"os/signal"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,8 +19,12 @@ import (
 
 // Graph represents an undirected graph with parallel edges.
 // The adjacency list maps each vertex to its list of connected vertices.
+// Members maps each vertex to the original vertices that have been contracted into it.
 type Graph struct {
 	AdjacencyList map[int][]int
+	Members       map[int][]int
+	vertices      []int      // The vertices in a fixed order, so a seeded source reproduces the same choices.
+	rng           *rand.Rand // The random source for edge selection; nil uses the global math/rand source.
 }
 
 // NewGraph creates a new graph from the given adjacency list.
@@ -22,10 +32,31 @@ type Graph struct {
 // the original graph remains unmodified during operations.
 func NewGraph(adjacencyList map[int][]int) *Graph {
 	newList := make(map[int][]int)
+	members := make(map[int][]int)
+	vertices := make([]int, 0, len(adjacencyList))
 	for key, value := range adjacencyList {
 		newList[key] = append([]int(nil), value...)
+		members[key] = []int{key}
+		vertices = append(vertices, key)
 	}
-	return &Graph{AdjacencyList: newList}
+	slices.Sort(vertices)
+	return &Graph{AdjacencyList: newList, Members: members, vertices: vertices}
+}
+
+// NewGraphWithRand creates a new graph like NewGraph that draws its random edges from rng.
+// Graphs built from the same adjacency list with identically seeded sources contract identically.
+func NewGraphWithRand(adjacencyList map[int][]int, rng *rand.Rand) *Graph {
+	graph := NewGraph(adjacencyList)
+	graph.rng = rng
+	return graph
+}
+
+// intn returns a random integer in [0, n) from the graph's random source.
+func (g *Graph) intn(n int) int {
+	if g.rng != nil {
+		return g.rng.Intn(n)
+	}
+	return rand.Intn(n)
 }
 
 // ContractEdge merges two vertices (u and v) into one.
@@ -52,22 +83,30 @@ func (g *Graph) ContractEdge(u, v int) {
 	}
 	g.AdjacencyList[u] = filtered
 
-	// Delete the merged vertex v from the graph.
+	// Delete the merged vertex v from the graph, moving its members into u.
+	g.Members[u] = append(g.Members[u], g.Members[v]...)
 	delete(g.AdjacencyList, v)
+	delete(g.Members, v)
+	if i := slices.Index(g.vertices, v); i >= 0 {
+		g.vertices = slices.Delete(g.vertices, i, i+1)
+	}
 }
 
 // GetRandomEdge selects a random edge (u, v) from the graph.
 // It chooses a random vertex u and then randomly selects one of its neighbors v.
 func (g *Graph) GetRandomEdge() (int, int) {
-	// Collect all vertices into a slice for random selection.
-	vertices := make([]int, 0, len(g.AdjacencyList))
-	for vertex := range g.AdjacencyList {
-		vertices = append(vertices, vertex)
+	// Rebuild the vertex order if the adjacency list was changed directly.
+	if len(g.vertices) != len(g.AdjacencyList) {
+		g.vertices = g.vertices[:0]
+		for vertex := range g.AdjacencyList {
+			g.vertices = append(g.vertices, vertex)
+		}
+		slices.Sort(g.vertices)
 	}
 
 	// Select a random vertex u and a random neighbor v.
-	u := vertices[rand.Intn(len(vertices))]
-	v := g.AdjacencyList[u][rand.Intn(len(g.AdjacencyList[u]))]
+	u := g.vertices[g.intn(len(g.vertices))]
+	v := g.AdjacencyList[u][g.intn(len(g.AdjacencyList[u]))]
 	return u, v
 }
 
@@ -87,6 +126,212 @@ func (g *Graph) GetMinCut() int {
 	return 0
 }
 
+// GetMinCutPartition runs the randomized contraction algorithm on the graph like GetMinCut,
+// and also returns the original vertices contracted into one of the two remaining vertices.
+func (g *Graph) GetMinCutPartition() ([]int, int) {
+	size := g.GetMinCut()
+	for _, vertex := range g.vertices {
+		return g.Members[vertex], size
+	}
+	return nil, size
+}
+
+// KargerStein runs one trial of the Karger–Stein recursive contraction algorithm on the graph.
+// Graphs with more than six vertices are contracted twice, independently, down to ⌈1 + n/√2⌉ vertices,
+// and the better of the two recursive results is kept; smaller graphs are solved by brute force.
+// A trial finds a minimum cut with probability Ω(1/log n), against Ω(1/n²) for a single plain contraction.
+// It returns the original vertices on one side of the cut found and the number of crossing edges.
+func (g *Graph) KargerStein() ([]int, int) {
+	return newDenseMultigraph(g).kargerStein()
+}
+
+// denseMultigraph stores a contracted multigraph as a matrix of edge multiplicities, so that copying
+// and contracting it cost O(n²) however many parallel edges build up. Karger–Stein copies the graph
+// at every level of its recursion, which makes this representation necessary for O(n² log n) trials.
+type denseMultigraph struct {
+	multiplicity [][]int // multiplicity[i][j] is the number of edges between vertices i and j.
+	degrees      []int
+	members      [][]int
+}
+
+// newDenseMultigraph converts a graph into its dense form.
+func newDenseMultigraph(g *Graph) *denseMultigraph {
+	vertices := make([]int, 0, len(g.AdjacencyList))
+	for vertex := range g.AdjacencyList {
+		vertices = append(vertices, vertex)
+	}
+	slices.Sort(vertices)
+	index := make(map[int]int, len(vertices))
+	for i, vertex := range vertices {
+		index[vertex] = i
+	}
+
+	n := len(vertices)
+	dense := &denseMultigraph{
+		multiplicity: make([][]int, n),
+		degrees:      make([]int, n),
+		members:      make([][]int, n),
+	}
+	for i, vertex := range vertices {
+		dense.multiplicity[i] = make([]int, n)
+		for _, neighbor := range g.AdjacencyList[vertex] {
+			if j, found := index[neighbor]; found && j != i {
+				dense.multiplicity[i][j]++
+				dense.degrees[i]++
+			}
+		}
+		dense.members[i] = append([]int(nil), g.Members[vertex]...)
+	}
+	return dense
+}
+
+// clone returns a deep copy of the multigraph.
+func (d *denseMultigraph) clone() *denseMultigraph {
+	n := len(d.degrees)
+	clone := &denseMultigraph{
+		multiplicity: make([][]int, n),
+		degrees:      append([]int(nil), d.degrees...),
+		members:      make([][]int, n),
+	}
+	for i := range n {
+		clone.multiplicity[i] = append([]int(nil), d.multiplicity[i]...)
+		clone.members[i] = append([]int(nil), d.members[i]...)
+	}
+	return clone
+}
+
+// randomEdge selects an edge uniformly at random: an endpoint u with probability proportional to its
+// degree, then a neighbor v with probability proportional to the number of edges between them.
+// ok is false if no edges remain, which happens when the input graph is disconnected.
+func (d *denseMultigraph) randomEdge() (u, v int, ok bool) {
+	total := 0
+	for _, degree := range d.degrees {
+		total += degree
+	}
+	if total == 0 {
+		return 0, 0, false
+	}
+	r := rand.Intn(total)
+	for r >= d.degrees[u] {
+		r -= d.degrees[u]
+		u++
+	}
+	for r >= d.multiplicity[u][v] {
+		r -= d.multiplicity[u][v]
+		v++
+	}
+	return u, v, true
+}
+
+// contract merges vertex v into u, dropping the edges between them, and removes v by moving the last
+// vertex into its place.
+func (d *denseMultigraph) contract(u, v int) {
+	d.degrees[u] += d.degrees[v] - 2*d.multiplicity[u][v]
+	for x := range d.degrees {
+		if x != u && x != v {
+			d.multiplicity[u][x] += d.multiplicity[v][x]
+			d.multiplicity[x][u] = d.multiplicity[u][x]
+		}
+	}
+	d.multiplicity[u][v], d.multiplicity[v][u] = 0, 0
+	d.members[u] = append(d.members[u], d.members[v]...)
+
+	last := len(d.degrees) - 1
+	if v != last {
+		copy(d.multiplicity[v], d.multiplicity[last])
+		for x := range d.degrees {
+			d.multiplicity[x][v] = d.multiplicity[x][last]
+		}
+		d.multiplicity[v][v] = 0
+		d.degrees[v] = d.degrees[last]
+		d.members[v] = d.members[last]
+	}
+	d.multiplicity = d.multiplicity[:last]
+	for x := range d.multiplicity {
+		d.multiplicity[x] = d.multiplicity[x][:last]
+	}
+	d.degrees = d.degrees[:last]
+	d.members = d.members[:last]
+}
+
+// contractTo contracts random edges until at most t vertices or no edges remain.
+func (d *denseMultigraph) contractTo(t int) {
+	for len(d.degrees) > t {
+		u, v, ok := d.randomEdge()
+		if !ok {
+			return
+		}
+		d.contract(u, v)
+	}
+}
+
+// kargerStein runs one Karger–Stein trial; see Graph.KargerStein.
+func (d *denseMultigraph) kargerStein() ([]int, int) {
+	n := len(d.degrees)
+	if n <= 6 {
+		return d.bruteForceMinCut()
+	}
+	// Without edges the graph is disconnected, and any vertex against the rest is a cut of size zero.
+	if slices.Max(d.degrees) == 0 {
+		return slices.Clone(d.members[0]), 0
+	}
+
+	target := int(math.Ceil(1 + float64(n)/math.Sqrt2))
+	var bestSide []int
+	bestSize := math.MaxInt
+	for i := 0; i < 2; i++ {
+		contracted := d.clone()
+		contracted.contractTo(target)
+		if side, size := contracted.kargerStein(); size < bestSize {
+			bestSide, bestSize = side, size
+		}
+	}
+	return bestSide, bestSize
+}
+
+// bruteForceMinCut finds the minimum cut of a small graph by trying every split of its vertices.
+// It returns the original vertices on one side of the best cut and the number of crossing edges.
+func (d *denseMultigraph) bruteForceMinCut() ([]int, int) {
+	n := len(d.degrees)
+	if n < 2 {
+		return nil, 0
+	}
+
+	// The last vertex always stays outside the set, so every split is tried exactly once.
+	bestMask, bestSize := 0, math.MaxInt
+	for mask := 1; mask < 1<<(n-1); mask++ {
+		size := 0
+		for i := range n {
+			for j := range n {
+				if mask&(1<<i) != 0 && mask&(1<<j) == 0 {
+					size += d.multiplicity[i][j]
+				}
+			}
+		}
+		if size < bestSize {
+			bestMask, bestSize = mask, size
+		}
+	}
+
+	var side []int
+	for i := range n {
+		if bestMask&(1<<i) != 0 {
+			side = append(side, d.members[i]...)
+		}
+	}
+	return side, bestSize
+}
+
+// Cut is a partition of the graph's vertices into two sides together with the edges that cross it.
+// SideA always contains the smallest vertex, and each crossing edge is listed once as (SideA vertex, SideB vertex).
+// Size is the number of crossing edges, or their total weight in a weighted graph.
+type Cut struct {
+	Size          int
+	SideA         []int
+	SideB         []int
+	CrossingEdges [][2]int
+}
+
 // RandomizedContractionAlgorithm represents the overall algorithm
 // and stores the original graph's adjacency list.
 type RandomizedContractionAlgorithm struct {
@@ -102,48 +347,372 @@ func NewRandomizedContractionAlgorithm(adjacencyList map[int][]int) *RandomizedC
 // FindMinCut performs multiple trials of the contraction algorithm to find the minimum cut.
 // It can run in parallel if the `parallel` flag is set to true.
 func (rca *RandomizedContractionAlgorithm) FindMinCut(trials int, parallel bool) int {
-	// If the number of trials is not specified, default to n^2, where n is the number of vertices.
+	options := MinCutOptions{Trials: trials, Workers: 1, Seed: rand.Int63()}
+	if parallel {
+		options.Workers = runtime.GOMAXPROCS(0)
+	}
+	minCut, _ := rca.FindMinCutContext(context.Background(), options)
+	return minCut
+}
+
+// MinCutOptions configures FindMinCutContext.
+type MinCutOptions struct {
+	// Trials is the number of contraction trials; 0 defaults to n^2, where n is the number of vertices.
+	Trials int
+	// Workers is the number of goroutines running trials; 0 defaults to GOMAXPROCS.
+	Workers int
+	// Seed determines the random choices: worker i draws from a source seeded with Seed + i and runs
+	// trials i, i + Workers, i + 2*Workers, ..., so the same Seed and Workers reproduce the same result.
+	Seed int64
+	// Progress, if not nil, is called from the calling goroutine every ProgressEvery completed trials
+	// and after the last one.
+	Progress func(MinCutProgress)
+	// ProgressEvery defaults to 1% of the trials.
+	ProgressEvery int
+}
+
+// MinCutProgress reports how far a FindMinCutContext run has come.
+type MinCutProgress struct {
+	TrialsDone int
+	Trials     int
+	BestCut    int
+}
+
+// FindMinCutContext performs contraction trials on a bounded pool of workers and returns the smallest cut found.
+// If ctx is cancelled before all trials finish, it returns the best cut found so far together with ctx.Err();
+// the cut is math.MaxInt if no trial has finished yet.
+func (rca *RandomizedContractionAlgorithm) FindMinCutContext(ctx context.Context, options MinCutOptions) (int, error) {
+	minCut := math.MaxInt
+	err := rca.runTrials(ctx, options, func(side []int, size int) int {
+		minCut = min(minCut, size)
+		return minCut
+	})
+	return minCut, err
+}
+
+// CutFrequency is a distinct cut together with the number of trials that found it.
+type CutFrequency struct {
+	Cut   Cut
+	Count int
+}
+
+// MinCutCensus groups the minimum cuts found by EnumerateMinCuts.
+type MinCutCensus struct {
+	// Trials is the number of completed trials.
+	Trials int
+	// MinCuts lists every distinct cut of the smallest size found, most frequent first.
+	MinCuts []CutFrequency
+}
+
+// SuccessRate returns the fraction of trials that found one of the minimum cuts,
+// an estimate of the per-trial success probability.
+func (census MinCutCensus) SuccessRate() float64 {
+	if census.Trials == 0 {
+		return 0
+	}
+	hits := 0
+	for _, cut := range census.MinCuts {
+		hits += cut.Count
+	}
+	return float64(hits) / float64(census.Trials)
+}
+
+// EnumerateMinCuts performs contraction trials like FindMinCutContext, but keeps the vertex partition
+// of every trial. Partitions are identified by their canonical form, the sorted side that contains the
+// smallest vertex, and the distinct partitions of the smallest size are returned with their frequencies.
+// Karger's analysis bounds the number of minimum cuts by C(n,2), and each one is found by a trial
+// with probability at least 1/C(n,2). On cancellation, the census so far is returned with ctx.Err().
+func (rca *RandomizedContractionAlgorithm) EnumerateMinCuts(ctx context.Context, options MinCutOptions) (MinCutCensus, error) {
+	vertices := make([]int, 0, len(rca.OriginalAdjacencyList))
+	for vertex := range rca.OriginalAdjacencyList {
+		vertices = append(vertices, vertex)
+	}
+
+	minCut := math.MaxInt
+	counts := make(map[string]int)
+	sides := make(map[string][]int)
+	trials := 0
+	err := rca.runTrials(ctx, options, func(side []int, size int) int {
+		trials++
+		if size > minCut {
+			return minCut
+		}
+		if size < minCut {
+			minCut = size
+			clear(counts)
+			clear(sides)
+		}
+		canonical, _ := canonicalSides(vertices, side)
+		key := fmt.Sprint(canonical)
+		if counts[key] == 0 {
+			sides[key] = canonical
+		}
+		counts[key]++
+		return minCut
+	})
+
+	census := MinCutCensus{Trials: trials}
+	for key, count := range counts {
+		census.MinCuts = append(census.MinCuts, CutFrequency{Cut: rca.cutFromSide(sides[key]), Count: count})
+	}
+	slices.SortFunc(census.MinCuts, func(a, b CutFrequency) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return slices.Compare(a.Cut.SideA, b.Cut.SideA)
+	})
+	return census, err
+}
+
+// runTrials performs contraction trials on a bounded pool of workers. Each finished trial is passed to
+// collect, always from the calling goroutine, with one side of its partition and its size; collect returns
+// the best cut so far for progress reports. It returns ctx.Err() if ctx is cancelled before all trials finish.
+func (rca *RandomizedContractionAlgorithm) runTrials(ctx context.Context, options MinCutOptions, collect func(side []int, size int) int) error {
+	trials := options.Trials
 	if trials == 0 {
 		n := len(rca.OriginalAdjacencyList)
 		trials = n * n
 	}
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, trials)
+	progressEvery := options.ProgressEvery
+	if progressEvery <= 0 {
+		progressEvery = max(trials/100, 1)
+	}
+
+	// Each worker runs its share of the trials with its own random source.
+	type trialResult struct {
+		side []int
+		size int
+	}
+	results := make(chan trialResult, workers)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(options.Seed + int64(worker)))
+			for trial := worker; trial < trials; trial += workers {
+				if ctx.Err() != nil {
+					return
+				}
+				graph := NewGraphWithRand(rca.OriginalAdjacencyList, rng)
+				side, size := graph.GetMinCutPartition()
+				select {
+				case results <- trialResult{side: side, size: size}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(worker)
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
 
-	minCut := int(^uint(0) >> 1) // Initialize to the maximum possible integer value.
+	// Collect the results in this goroutine.
+	done := 0
+	for result := range results {
+		done++
+		best := collect(result.side, result.size)
+		if options.Progress != nil && (done%progressEvery == 0 || done == trials) {
+			options.Progress(MinCutProgress{TrialsDone: done, Trials: trials, BestCut: best})
+		}
+	}
 
-	if parallel {
-		// Run trials in parallel using goroutines.
-		var wg sync.WaitGroup
-		resultChan := make(chan int, trials)
+	if done < trials {
+		return ctx.Err()
+	}
+	return nil
+}
 
-		for i := 0; i < trials; i++ {
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				graph := NewGraph(rca.OriginalAdjacencyList)
-				resultChan <- graph.GetMinCut()
-			}()
+// FindMinCutKargerStein performs multiple trials of the Karger–Stein algorithm and returns the best cut found.
+// If the number of trials is 0, it defaults to ⌈log2 n⌉², which finds a minimum cut with high probability.
+func (rca *RandomizedContractionAlgorithm) FindMinCutKargerStein(trials int) Cut {
+	if trials == 0 {
+		logN := int(math.Ceil(math.Log2(float64(len(rca.OriginalAdjacencyList)))))
+		trials = max(logN*logN, 1)
+	}
+
+	var bestSide []int
+	bestSize := math.MaxInt
+	for i := 0; i < trials; i++ {
+		graph := NewGraph(rca.OriginalAdjacencyList)
+		if side, size := graph.KargerStein(); size < bestSize {
+			bestSide, bestSize = side, size
 		}
+	}
+	return rca.cutFromSide(bestSide)
+}
 
-		wg.Wait()
-		close(resultChan)
+// cutFromSide builds the cut that separates the given vertices from the rest of the original graph.
+func (rca *RandomizedContractionAlgorithm) cutFromSide(side []int) Cut {
+	vertices := make([]int, 0, len(rca.OriginalAdjacencyList))
+	for vertex := range rca.OriginalAdjacencyList {
+		vertices = append(vertices, vertex)
+	}
+	sideA, sideB := canonicalSides(vertices, side)
+
+	// Every edge appears in the adjacency lists of both endpoints, so scanning one side lists it once.
+	cut := Cut{SideA: sideA, SideB: sideB}
+	inA := make(map[int]bool, len(sideA))
+	for _, vertex := range sideA {
+		inA[vertex] = true
+	}
+	for _, u := range sideA {
+		for _, v := range rca.OriginalAdjacencyList[u] {
+			if !inA[v] {
+				cut.CrossingEdges = append(cut.CrossingEdges, [2]int{u, v})
+			}
+		}
+	}
+	cut.Size = len(cut.CrossingEdges)
+	return cut
+}
+
+// canonicalSides splits the vertices into those in side and the rest, both sorted,
+// and returns first whichever part contains the smallest vertex.
+func canonicalSides(vertices, side []int) ([]int, []int) {
+	inSide := make(map[int]bool, len(side))
+	for _, vertex := range side {
+		inSide[vertex] = true
+	}
+
+	var sideA, sideB []int
+	for _, vertex := range vertices {
+		if inSide[vertex] {
+			sideA = append(sideA, vertex)
+		} else {
+			sideB = append(sideB, vertex)
+		}
+	}
+	slices.Sort(sideA)
+	slices.Sort(sideB)
+	if len(sideA) == 0 || (len(sideB) > 0 && sideB[0] < sideA[0]) {
+		sideA, sideB = sideB, sideA
+	}
+	return sideA, sideB
+}
 
-		// Collect results from the channel and update the minimum cut.
-		for result := range resultChan {
-			if result < minCut {
-				minCut = result
+// StoerWagnerMinCut finds a minimum cut of a connected, weighted undirected graph deterministically.
+// The graph maps each vertex to its neighbors and the weights of the edges to them, in both directions.
+// Each phase grows a maximum adjacency ordering; the last vertex added, separated from all others,
+// is a minimum cut between the last two vertices, which are then merged. The best of these n - 1
+// phase cuts is a global minimum cut. With a weight matrix this runs in O(n³) time.
+func StoerWagnerMinCut(weights map[int]map[int]int) (Cut, error) {
+	vertices := make([]int, 0, len(weights))
+	for vertex := range weights {
+		vertices = append(vertices, vertex)
+	}
+	slices.Sort(vertices)
+	n := len(vertices)
+	if n < 2 {
+		return Cut{}, fmt.Errorf("a cut needs at least two vertices, got %d", n)
+	}
+	index := make(map[int]int, n)
+	for i, vertex := range vertices {
+		index[vertex] = i
+	}
+
+	// Build the weight matrix; members[i] lists the original vertices merged into i.
+	matrix := make([][]int, n)
+	members := make([][]int, n)
+	for i, vertex := range vertices {
+		matrix[i] = make([]int, n)
+		for neighbor, weight := range weights[vertex] {
+			if j, found := index[neighbor]; found && j != i {
+				matrix[i][j] = weight
 			}
 		}
-	} else {
-		// Run trials sequentially.
-		for i := 0; i < trials; i++ {
-			graph := NewGraph(rca.OriginalAdjacencyList)
-			if cut := graph.GetMinCut(); cut < minCut {
-				minCut = cut
+		members[i] = []int{vertex}
+	}
+
+	active := make([]int, n)
+	for i := range active {
+		active[i] = i
+	}
+	bestWeight := math.MaxInt
+	var bestSide []int
+	key := make([]int, n)
+	added := make([]bool, n)
+	for len(active) > 1 {
+		// Maximum adjacency ordering: repeatedly add the vertex most tightly connected to those added.
+		for _, v := range active {
+			key[v], added[v] = 0, false
+		}
+		previous, last := -1, -1
+		for range active {
+			next := -1
+			for _, v := range active {
+				if !added[v] && (next == -1 || key[v] > key[next]) {
+					next = v
+				}
+			}
+			added[next] = true
+			previous, last = last, next
+			for _, v := range active {
+				if !added[v] {
+					key[v] += matrix[next][v]
+				}
 			}
 		}
+
+		// The cut of the phase separates the last vertex from the rest.
+		if key[last] < bestWeight {
+			bestWeight = key[last]
+			bestSide = append([]int(nil), members[last]...)
+		}
+
+		// Merge the last vertex into the one added before it.
+		members[previous] = append(members[previous], members[last]...)
+		for _, v := range active {
+			matrix[previous][v] += matrix[last][v]
+			matrix[v][previous] = matrix[previous][v]
+		}
+		matrix[previous][previous] = 0
+		active = slices.DeleteFunc(active, func(v int) bool { return v == last })
 	}
 
-	return minCut
+	// List the crossing edges with their total weight.
+	sideA, sideB := canonicalSides(vertices, bestSide)
+	cut := Cut{SideA: sideA, SideB: sideB}
+	inA := make(map[int]bool, len(sideA))
+	for _, vertex := range sideA {
+		inA[vertex] = true
+	}
+	for _, u := range sideA {
+		neighbors := make([]int, 0, len(weights[u]))
+		for v := range weights[u] {
+			if !inA[v] {
+				neighbors = append(neighbors, v)
+			}
+		}
+		slices.Sort(neighbors)
+		for _, v := range neighbors {
+			cut.CrossingEdges = append(cut.CrossingEdges, [2]int{u, v})
+			cut.Size += weights[u][v]
+		}
+	}
+	return cut, nil
+}
+
+// WeightsFromAdjacencyList converts an unweighted adjacency list into edge weights,
+// where the weight of an edge is its number of parallel copies.
+func WeightsFromAdjacencyList(adjacencyList map[int][]int) map[int]map[int]int {
+	weights := make(map[int]map[int]int, len(adjacencyList))
+	for vertex, neighbors := range adjacencyList {
+		weights[vertex] = make(map[int]int)
+		for _, neighbor := range neighbors {
+			if neighbor != vertex {
+				weights[vertex][neighbor]++
+			}
+		}
+	}
+	return weights
 }
 
 // ParseAdjacencyList parses the adjacency list from the input file.
@@ -185,25 +754,272 @@ func ParseAdjacencyList(filepath string) (map[int][]int, error) {
 	return adjacencyList, nil
 }
 
+// ParseWeightedAdjacencyList parses a weighted adjacency list from the input file.
+// Each line holds a vertex followed by its neighbors in the kargerMinCut format; a neighbor may carry
+// an optional weight column as "neighbor,weight", and a bare neighbor has weight 1. Parallel entries
+// add up. An edge listed by only one endpoint is mirrored; one listed by both must have the same weight.
+func ParseWeightedAdjacencyList(filepath string) (map[int]map[int]int, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	weights := make(map[int]map[int]int)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 2 {
+			continue // Skip invalid lines.
+		}
+
+		vertex, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if weights[vertex] == nil {
+			weights[vertex] = make(map[int]int)
+		}
+		for _, entry := range parts[1:] {
+			neighborText, weightText, hasWeight := strings.Cut(entry, ",")
+			neighbor, err := strconv.Atoi(neighborText)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			weight := 1
+			if hasWeight {
+				if weight, err = strconv.Atoi(weightText); err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+				}
+				if weight < 0 {
+					return nil, fmt.Errorf("line %d: negative weight %d", lineNumber, weight)
+				}
+			}
+			if neighbor != vertex {
+				weights[vertex][neighbor] += weight
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Make the weights symmetric.
+	for u, neighbors := range weights {
+		for v, weight := range neighbors {
+			if weights[v] == nil {
+				weights[v] = make(map[int]int)
+			}
+			reverse, listed := weights[v][u]
+			if !listed {
+				weights[v][u] = weight
+			} else if reverse != weight {
+				return nil, fmt.Errorf("edge %d-%d has weight %d but %d-%d has weight %d", u, v, weight, v, u, reverse)
+			}
+		}
+	}
+	return weights, nil
+}
+
+// randomConnectedMultigraph generates a connected multigraph on vertices 1..n: a random spanning tree
+// plus extra random edges, which may be parallel.
+func randomConnectedMultigraph(n, extraEdges int) map[int][]int {
+	adjacencyList := make(map[int][]int, n)
+	addEdge := func(u, v int) {
+		adjacencyList[u] = append(adjacencyList[u], v)
+		adjacencyList[v] = append(adjacencyList[v], u)
+	}
+	for v := 2; v <= n; v++ {
+		addEdge(v, 1+rand.Intn(v-1))
+	}
+	for i := 0; i < extraEdges; i++ {
+		u, v := 1+rand.Intn(n), 1+rand.Intn(n)
+		if u != v {
+			addEdge(u, v)
+		}
+	}
+	return adjacencyList
+}
+
+// verifyAgainstStoerWagner compares the randomized algorithms with Stoer–Wagner on random small multigraphs
+// and returns an error describing the first mismatch. Both randomized algorithms can miss the minimum, so
+// they get enough trials to make that negligible: 10n² contractions all miss with probability below e^-20,
+// and 4⌈log2 n⌉² Karger–Stein trials are four times its high-probability default.
+func verifyAgainstStoerWagner(graphs int) error {
+	for i := 0; i < graphs; i++ {
+		n := 6 + rand.Intn(10)
+		adjacencyList := randomConnectedMultigraph(n, n+rand.Intn(3*n))
+		expected, err := StoerWagnerMinCut(WeightsFromAdjacencyList(adjacencyList))
+		if err != nil {
+			return err
+		}
+
+		rca := NewRandomizedContractionAlgorithm(adjacencyList)
+		if size := rca.FindMinCut(10*n*n, false); size != expected.Size {
+			return fmt.Errorf("graph %d with %d vertices: contraction found %d, Stoer–Wagner %d", i+1, n, size, expected.Size)
+		}
+		logN := int(math.Ceil(math.Log2(float64(n))))
+		if cut := rca.FindMinCutKargerStein(4 * logN * logN); cut.Size != expected.Size {
+			return fmt.Errorf("graph %d with %d vertices: Karger–Stein found %d, Stoer–Wagner %d", i+1, n, cut.Size, expected.Size)
+		}
+	}
+	return nil
+}
+
+// printCensus prints the distinct minimum cuts with their frequencies next to Karger's per-trial bound.
+// Each cut is shown by its smaller side.
+func printCensus(census MinCutCensus, n int) {
+	if len(census.MinCuts) == 0 {
+		fmt.Println("No trials finished.")
+		return
+	}
+	fmt.Printf("Minimum cut: %d\n", census.MinCuts[0].Cut.Size)
+	fmt.Printf("Distinct minimum cuts: %d (at most C(n,2) = %d)\n", len(census.MinCuts), n*(n-1)/2)
+	fmt.Printf("Per-trial success rate: %.4f over %d trials (Karger's lower bound 1/C(n,2) = %.6f)\n",
+		census.SuccessRate(), census.Trials, 2/float64(n*(n-1)))
+	for _, frequency := range census.MinCuts {
+		cut := frequency.Cut
+		smaller := cut.SideA
+		if len(cut.SideB) < len(smaller) {
+			smaller = cut.SideB
+		}
+		fmt.Printf("  %6d hits (%6.2f%%): %d|%d split, smaller side %v\n", frequency.Count,
+			100*float64(frequency.Count)/float64(census.Trials), len(cut.SideA), len(cut.SideB), smaller)
+	}
+}
+
 func main() {
+	algorithm := flag.String("algorithm", "contraction", "algorithm to run: contraction, census, karger-stein or stoer-wagner")
+	trials := flag.Int("trials", 0, "number of trials (0 chooses a default from the number of vertices)")
+	workers := flag.Int("workers", 0, "contraction and census: number of worker goroutines (0 uses GOMAXPROCS)")
+	seed := flag.Int64("seed", 0, "contraction and census: random seed for reproducible runs (0 picks one from the clock)")
+	timeout := flag.Duration("timeout", 0, "contraction and census: stop after this long and report the best cut so far (0 means no limit)")
+	progress := flag.Bool("progress", false, "contraction and census: report progress every 10% of the trials")
+	filepath := flag.String("input", "course_1/module_4/programming_assignment_4/kargerMinCut.txt",
+		"adjacency list file; stoer-wagner also accepts neighbor,weight entries")
+	verify := flag.Bool("verify", false, "check the randomized algorithms against Stoer–Wagner")
+	flag.Parse()
+
 	// Seed the random number generator for reproducibility.
 	rand.Seed(time.Now().UnixNano())
 
-	// Specify the path to the adjacency list file.
-	filepath := "course_1/module_4/programming_assignment_4/kargerMinCut.txt"
+	if *algorithm == "stoer-wagner" {
+		fmt.Println("Running Stoer–Wagner minimum cut algorithm...")
+		weights, err := ParseWeightedAdjacencyList(*filepath)
+		if err != nil {
+			fmt.Printf("Error reading adjacency list: %v\n", err)
+			os.Exit(1)
+		}
+		cut, err := StoerWagnerMinCut(weights)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Minimum cut weight: %d\n", cut.Size)
+		fmt.Printf("Side A (%d vertices): %v\n", len(cut.SideA), cut.SideA)
+		fmt.Printf("Side B (%d vertices): %v\n", len(cut.SideB), cut.SideB)
+		fmt.Printf("Crossing edges: %v\n", cut.CrossingEdges)
+		return
+	}
 
 	// Parse the adjacency list from the file.
-	adjacencyList, err := ParseAdjacencyList(filepath)
+	adjacencyList, err := ParseAdjacencyList(*filepath)
 	if err != nil {
 		fmt.Printf("Error reading adjacency list: %v\n", err)
-		return
+		os.Exit(1)
 	}
 
 	// Create an instance of the RandomizedContractionAlgorithm.
 	rca := NewRandomizedContractionAlgorithm(adjacencyList)
-	fmt.Println("Running randomized contraction algorithm...")
 
-	// Run the algorithm to find the minimum cut.
-	minCut := rca.FindMinCut(0, true) // 0 for auto-calculating trials, true for parallel execution.
-	fmt.Printf("Minimum cut: %d\n", minCut)
+	// The size of the cut found, checked against Stoer–Wagner with -verify; -1 if no trial finished.
+	found := -1
+	switch *algorithm {
+	case "contraction", "census":
+		if *algorithm == "census" {
+			fmt.Println("Running randomized contraction algorithm and grouping the minimum cuts found...")
+		} else {
+			fmt.Println("Running randomized contraction algorithm...")
+		}
+
+		// Stop early on Ctrl+C or when the timeout expires.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+
+		options := MinCutOptions{Trials: *trials, Workers: *workers, Seed: *seed}
+		if options.Seed == 0 {
+			options.Seed = time.Now().UnixNano()
+		}
+		if *progress {
+			options.ProgressEvery = max(*trials/10, 1)
+			if *trials == 0 {
+				options.ProgressEvery = max(len(adjacencyList)*len(adjacencyList)/10, 1)
+			}
+			options.Progress = func(p MinCutProgress) {
+				fmt.Printf("Progress: %d/%d trials, best cut so far %d\n", p.TrialsDone, p.Trials, p.BestCut)
+			}
+		}
+		fmt.Printf("Seed: %d\n", options.Seed)
+
+		if *algorithm == "census" {
+			census, err := rca.EnumerateMinCuts(ctx, options)
+			if err != nil {
+				fmt.Println("Stopped early:", err)
+			}
+			printCensus(census, len(adjacencyList))
+			if len(census.MinCuts) > 0 {
+				found = census.MinCuts[0].Cut.Size
+			}
+			break
+		}
+
+		// Run the algorithm to find the minimum cut.
+		minCut, err := rca.FindMinCutContext(ctx, options)
+		if err != nil {
+			fmt.Println("Stopped early:", err)
+		}
+		fmt.Printf("Minimum cut: %d\n", minCut)
+		if minCut != math.MaxInt {
+			found = minCut
+		}
+	case "karger-stein":
+		fmt.Println("Running Karger–Stein recursive contraction algorithm...")
+
+		cut := rca.FindMinCutKargerStein(*trials)
+		found = cut.Size
+		fmt.Printf("Minimum cut: %d\n", cut.Size)
+		fmt.Printf("Side A (%d vertices): %v\n", len(cut.SideA), cut.SideA)
+		fmt.Printf("Side B (%d vertices): %v\n", len(cut.SideB), cut.SideB)
+		fmt.Printf("Crossing edges: %v\n", cut.CrossingEdges)
+	default:
+		fmt.Println("Error: unknown algorithm", *algorithm)
+		os.Exit(2)
+	}
+
+	if *verify {
+		// The deterministic answer for the input graph.
+		expected, err := StoerWagnerMinCut(WeightsFromAdjacencyList(adjacencyList))
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Stoer–Wagner minimum cut: %d\n", expected.Size)
+		if found >= 0 && found != expected.Size {
+			fmt.Printf("Mismatch: the randomized algorithm found %d; rerun with more -trials\n", found)
+			os.Exit(1)
+		}
+		if err := verifyAgainstStoerWagner(50); err != nil {
+			fmt.Println("Mismatch on a random multigraph:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Random multigraphs: the minimum cut matches Stoer–Wagner on all 50")
+	}
 }
@@ -2,30 +2,115 @@ package main
 
 import (
 	"container/list"
+	"errors"
+	"flag"
 	"fmt"
+	"iter"
+	"os"
+	"slices"
+	"strings"
 )
 
 // BFSGraph represents an undirected graph using an adjacency list.
 type BFSGraph struct {
 	adjacencyList map[int][]int // The adjacency list to store graph edges.
+	weights       map[int][]int // The weight of each edge, aligned with adjacencyList.
 }
 
 // NewBFSGraph creates and initializes a new BFSGraph.
 func NewBFSGraph() *BFSGraph {
 	return &BFSGraph{
 		adjacencyList: make(map[int][]int),
+		weights:       make(map[int][]int),
 	}
 }
 
-// AddEdge adds an undirected edge between nodes u and v.
+// AddEdge adds an undirected edge of weight 1 between nodes u and v.
 //
 // Args:
 //
 //	u: The start node of the edge.
 //	v: The end node of the edge.
 func (g *BFSGraph) AddEdge(u, v int) {
+	g.addEdge(u, v, 1)
+}
+
+// AddWeightedEdge adds an undirected edge between nodes u and v with a weight of 0 or 1, for ZeroOneBFS.
+// BFS and the other searches ignore weights and count every edge as one step.
+//
+// Args:
+//
+//	u: The start node of the edge.
+//	v: The end node of the edge.
+//	weight: The weight of the edge, 0 or 1.
+//
+// Returns:
+//
+//	An error if the weight is neither 0 nor 1.
+func (g *BFSGraph) AddWeightedEdge(u, v, weight int) error {
+	if weight != 0 && weight != 1 {
+		return fmt.Errorf("edge (%d, %d) has weight %d, expected 0 or 1", u, v, weight)
+	}
+	g.addEdge(u, v, weight)
+	return nil
+}
+
+// addEdge stores an undirected edge in both adjacency lists.
+func (g *BFSGraph) addEdge(u, v, weight int) {
 	g.adjacencyList[u] = append(g.adjacencyList[u], v)
+	g.weights[u] = append(g.weights[u], weight)
 	g.adjacencyList[v] = append(g.adjacencyList[v], u)
+	g.weights[v] = append(g.weights[v], weight)
+}
+
+// ShortestPathTree is the result of a search from one or more sources.
+// Every reached node has a distance, a parent on a shortest path back to its source, and the source itself.
+// Sources have no parent.
+type ShortestPathTree struct {
+	Distances map[int]int // The shortest distance to each reached node.
+	Parents   map[int]int // The previous node on a shortest path to each reached node.
+	Sources   map[int]int // The source each reached node is closest to.
+}
+
+// newShortestPathTree creates a tree containing only the given sources.
+func newShortestPathTree(sources []int) *ShortestPathTree {
+	tree := &ShortestPathTree{
+		Distances: make(map[int]int),
+		Parents:   make(map[int]int),
+		Sources:   make(map[int]int),
+	}
+	for _, source := range sources {
+		tree.Distances[source] = 0
+		tree.Sources[source] = source
+	}
+	return tree
+}
+
+// PathTo reconstructs a shortest path from the nearest source to the target by following parents.
+//
+// Args:
+//
+//	target: The node to reconstruct the path to.
+//
+// Returns:
+//
+//	The nodes on the path, starting at a source and ending at the target.
+//	If the target was not reached, returns an error.
+func (t *ShortestPathTree) PathTo(target int) ([]int, error) {
+	if _, reached := t.Distances[target]; !reached {
+		return nil, fmt.Errorf("node %d is not reachable from the sources", target)
+	}
+	path := []int{target}
+	for node := target; ; {
+		parent, hasParent := t.Parents[node]
+		if !hasParent {
+			break
+		}
+		path = append(path, parent)
+		node = parent
+	}
+	slices.Reverse(path)
+	return path, nil
 }
 
 // BFS performs Breadth-First Search starting from a given node.
@@ -40,18 +125,72 @@ func (g *BFSGraph) AddEdge(u, v int) {
 //	A map where keys are nodes and values are their distances from the start node.
 //	If the start node does not exist in the graph, returns an error.
 func (g *BFSGraph) BFS(start int) (map[int]int, error) {
-	if _, exists := g.adjacencyList[start]; !exists {
-		return nil, fmt.Errorf("start node %d not found in the graph", start)
+	tree, err := g.BFSTree(start)
+	if err != nil {
+		return nil, err
 	}
+	return tree.Distances, nil
+}
 
-	distances := make(map[int]int) // Store distances from the start node.
-	visited := make(map[int]bool)  // Track visited nodes.
-	queue := list.New()            // Queue for BFS.
+// BFSTree performs Breadth-First Search starting from a given node and keeps the shortest-path tree.
+//
+// Args:
+//
+//	start: The starting node for the BFS traversal.
+//
+// Returns:
+//
+//	The shortest-path tree, from which the path to any reached node can be reconstructed.
+//	If the start node does not exist in the graph, returns an error.
+func (g *BFSGraph) BFSTree(start int) (*ShortestPathTree, error) {
+	return g.MultiSourceBFS([]int{start})
+}
 
-	// Initialize BFS.
-	queue.PushBack(start)
-	visited[start] = true
-	distances[start] = 0
+// MultiSourceBFS performs Breadth-First Search from several start nodes at once.
+// Each node's distance is to its nearest start node, which is recorded in the tree's Sources.
+//
+// Args:
+//
+//	sources: The starting nodes for the BFS traversal.
+//
+// Returns:
+//
+//	The shortest-path forest rooted at the start nodes.
+//	If no start node is given or one does not exist in the graph, returns an error.
+func (g *BFSGraph) MultiSourceBFS(sources []int) (*ShortestPathTree, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one start node is required")
+	}
+	for _, source := range sources {
+		if _, exists := g.adjacencyList[source]; !exists {
+			return nil, fmt.Errorf("start node %d not found in the graph", source)
+		}
+	}
+
+	return ImplicitBFS(sources, func(node int) iter.Seq[int] {
+		return slices.Values(g.adjacencyList[node])
+	}), nil
+}
+
+// ImplicitBFS performs multi-source Breadth-First Search on a graph given by a neighbor function,
+// so that graphs such as grids can be searched without building an adjacency list.
+//
+// Args:
+//
+//	sources: The starting nodes for the BFS traversal.
+//	neighbors: Returns the neighbors of a node.
+//
+// Returns:
+//
+//	The shortest-path forest rooted at the start nodes.
+func ImplicitBFS(sources []int, neighbors func(node int) iter.Seq[int]) *ShortestPathTree {
+	tree := newShortestPathTree(sources) // Sources are visited at distance 0.
+	queue := list.New()                  // Queue for BFS.
+
+	// Initialize BFS with every source.
+	for _, source := range sources {
+		queue.PushBack(source)
+	}
 
 	// Perform BFS traversal.
 	for queue.Len() > 0 {
@@ -61,16 +200,142 @@ func (g *BFSGraph) BFS(start int) (map[int]int, error) {
 		current := element.Value.(int)
 
 		// Explore all neighbors of the current node.
-		for _, neighbor := range g.adjacencyList[current] {
-			if !visited[neighbor] {
-				visited[neighbor] = true
+		for neighbor := range neighbors(current) {
+			if _, visited := tree.Distances[neighbor]; !visited {
 				queue.PushBack(neighbor)
-				distances[neighbor] = distances[current] + 1
+				tree.Distances[neighbor] = tree.Distances[current] + 1
+				tree.Parents[neighbor] = current
+				tree.Sources[neighbor] = tree.Sources[current]
+			}
+		}
+	}
+
+	return tree
+}
+
+// ZeroOneBFS computes shortest paths from a given node in a graph whose edges weigh 0 or 1.
+// It keeps a deque instead of a queue: nodes reached over a 0-edge go to the front and nodes reached
+// over a 1-edge to the back, so nodes leave the deque in order of distance, as in Dijkstra's algorithm,
+// but in O(V + E) time.
+//
+// Args:
+//
+//	start: The starting node for the search.
+//
+// Returns:
+//
+//	The shortest-path tree with weighted distances.
+//	If the start node does not exist in the graph, returns an error.
+func (g *BFSGraph) ZeroOneBFS(start int) (*ShortestPathTree, error) {
+	if _, exists := g.adjacencyList[start]; !exists {
+		return nil, fmt.Errorf("start node %d not found in the graph", start)
+	}
+
+	tree := newShortestPathTree([]int{start})
+	deque := list.New()
+	deque.PushBack(start)
+
+	for deque.Len() > 0 {
+		element := deque.Front()
+		deque.Remove(element)
+
+		current := element.Value.(int)
+
+		// Relax every edge; a node may be pushed more than once, but only improvements are pushed.
+		for i, neighbor := range g.adjacencyList[current] {
+			weight := g.weights[current][i]
+			distance := tree.Distances[current] + weight
+			if known, reached := tree.Distances[neighbor]; reached && known <= distance {
+				continue
+			}
+			tree.Distances[neighbor] = distance
+			tree.Parents[neighbor] = current
+			tree.Sources[neighbor] = start
+			if weight == 0 {
+				deque.PushFront(neighbor)
+			} else {
+				deque.PushBack(neighbor)
+			}
+		}
+	}
+
+	return tree, nil
+}
+
+// IsBipartite checks whether the graph's nodes can be split into two sides with every edge between them.
+// Each connected component is 2-colored by BFS; a graph is bipartite exactly when it has no odd cycle.
+//
+// Returns:
+//
+//	A coloring with colors 0 and 1 and nil if the graph is bipartite.
+//	Otherwise nil and an odd cycle as a list of nodes, where consecutive nodes, and the last and first
+//	node, are adjacent.
+func (g *BFSGraph) IsBipartite() (map[int]int, []int) {
+	colors := make(map[int]int)
+	parents := make(map[int]int)
+
+	// Visit nodes in sorted order so that the reported cycle does not depend on map iteration.
+	nodes := make([]int, 0, len(g.adjacencyList))
+	for node := range g.adjacencyList {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+
+	for _, root := range nodes {
+		if _, colored := colors[root]; colored {
+			continue
+		}
+		colors[root] = 0
+		queue := list.New()
+		queue.PushBack(root)
+
+		for queue.Len() > 0 {
+			element := queue.Front()
+			queue.Remove(element)
+
+			current := element.Value.(int)
+			for _, neighbor := range g.adjacencyList[current] {
+				color, colored := colors[neighbor]
+				if !colored {
+					colors[neighbor] = 1 - colors[current]
+					parents[neighbor] = current
+					queue.PushBack(neighbor)
+				} else if color == colors[current] {
+					return nil, oddCycle(parents, root, current, neighbor)
+				}
 			}
 		}
 	}
 
-	return distances, nil
+	return colors, nil
+}
+
+// oddCycle builds the cycle closed by the edge (u, v) between two nodes of the same BFS color.
+// In a BFS tree both nodes then lie at the same depth, so the paths from them up to their lowest common
+// ancestor have equal length, and together with the edge they form a cycle of odd length.
+func oddCycle(parents map[int]int, root, u, v int) []int {
+	pathToRoot := func(node int) []int {
+		path := []int{node}
+		for node != root {
+			node = parents[node]
+			path = append(path, node)
+		}
+		return path
+	}
+	fromU, fromV := pathToRoot(u), pathToRoot(v)
+
+	// Strip the common part of the two paths, keeping their lowest common ancestor once.
+	for len(fromU) > 1 && len(fromV) > 1 && fromU[len(fromU)-2] == fromV[len(fromV)-2] {
+		fromU = fromU[:len(fromU)-1]
+		fromV = fromV[:len(fromV)-1]
+	}
+
+	// u ... ancestor ... v, and the edge (v, u) closes the cycle.
+	cycle := append([]int(nil), fromU...)
+	for i := len(fromV) - 2; i >= 0; i-- {
+		cycle = append(cycle, fromV[i])
+	}
+	return cycle
 }
 
 // ConnectedComponents identifies all connected components in the graph.
@@ -116,7 +381,199 @@ func (g *BFSGraph) ConnectedComponents() [][]int {
 	return components
 }
 
+// Neighborhood selects which grid cells are adjacent to a cell.
+type Neighborhood int
+
+const (
+	// FourNeighborhood connects a cell to the cells above, below, left and right of it.
+	FourNeighborhood Neighborhood = 4
+	// EightNeighborhood also connects the four diagonal cells. A diagonal move may not cut a corner:
+	// both cells it passes between must be open.
+	EightNeighborhood Neighborhood = 8
+)
+
+// Maze is an ASCII grid: '#' marks a wall, 'S' the start, 'G' the goal, and any other character an open cell.
+// Cells are numbered row by row, cell = row*Width + column, and a short row is treated as walled off
+// past its end.
+type Maze struct {
+	Width  int
+	Height int
+	Start  int
+	Goal   int
+	rows   []string
+}
+
+// ParseMaze parses an ASCII maze with exactly one start and one goal.
+//
+// Args:
+//
+//	text: The maze, one row per line.
+//
+// Returns:
+//
+//	The parsed maze, or an error if the start or goal is missing or repeated.
+func ParseMaze(text string) (*Maze, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("maze is empty")
+	}
+
+	maze := &Maze{Height: len(lines), Start: -1, Goal: -1, rows: lines}
+	for _, line := range lines {
+		maze.Width = max(maze.Width, len(line))
+	}
+	for row, line := range lines {
+		for column := 0; column < len(line); column++ {
+			cell := maze.Cell(row, column)
+			switch line[column] {
+			case 'S':
+				if maze.Start >= 0 {
+					return nil, fmt.Errorf("maze has a second start at row %d, column %d", row, column)
+				}
+				maze.Start = cell
+			case 'G':
+				if maze.Goal >= 0 {
+					return nil, fmt.Errorf("maze has a second goal at row %d, column %d", row, column)
+				}
+				maze.Goal = cell
+			}
+		}
+	}
+	if maze.Start < 0 {
+		return nil, errors.New("maze has no start 'S'")
+	}
+	if maze.Goal < 0 {
+		return nil, errors.New("maze has no goal 'G'")
+	}
+	return maze, nil
+}
+
+// Cell returns the number of the cell at the given row and column.
+func (m *Maze) Cell(row, column int) int {
+	return row*m.Width + column
+}
+
+// Position returns the row and column of a cell.
+func (m *Maze) Position(cell int) (int, int) {
+	return cell / m.Width, cell % m.Width
+}
+
+// IsOpen reports whether the cell at the given row and column is inside the maze and not a wall.
+func (m *Maze) IsOpen(row, column int) bool {
+	return row >= 0 && row < m.Height && column >= 0 && column < len(m.rows[row]) && m.rows[row][column] != '#'
+}
+
+// Neighbors returns the open cells adjacent to a cell in the given neighborhood.
+func (m *Maze) Neighbors(cell int, neighborhood Neighborhood) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		row, column := m.Position(cell)
+		for dr := -1; dr <= 1; dr++ {
+			for dc := -1; dc <= 1; dc++ {
+				if dr == 0 && dc == 0 || !m.IsOpen(row+dr, column+dc) {
+					continue
+				}
+				if dr != 0 && dc != 0 {
+					if neighborhood != EightNeighborhood || !m.IsOpen(row+dr, column) || !m.IsOpen(row, column+dc) {
+						continue
+					}
+				}
+				if !yield(m.Cell(row+dr, column+dc)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ShortestPath finds a shortest path from the start to the goal with BFS over the implicit grid graph.
+//
+// Args:
+//
+//	neighborhood: Whether moves are 4- or 8-directional.
+//
+// Returns:
+//
+//	The cells on the path from the start to the goal, or an error if the goal cannot be reached.
+func (m *Maze) ShortestPath(neighborhood Neighborhood) ([]int, error) {
+	tree := ImplicitBFS([]int{m.Start}, func(cell int) iter.Seq[int] {
+		return m.Neighbors(cell, neighborhood)
+	})
+	path, err := tree.PathTo(m.Goal)
+	if err != nil {
+		return nil, errors.New("the goal cannot be reached from the start")
+	}
+	return path, nil
+}
+
+// Render returns the maze with the cells of the path, other than the start and goal, drawn as '*'.
+func (m *Maze) Render(path []int) string {
+	rows := make([][]byte, m.Height)
+	for row, line := range m.rows {
+		rows[row] = []byte(line)
+	}
+	for _, cell := range path {
+		if cell != m.Start && cell != m.Goal {
+			row, column := m.Position(cell)
+			rows[row][column] = '*'
+		}
+	}
+
+	var builder strings.Builder
+	for _, row := range rows {
+		builder.Write(row)
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// solveMaze prints the shortest path through the maze in both neighborhoods.
+func solveMaze(maze *Maze) {
+	for _, neighborhood := range []Neighborhood{FourNeighborhood, EightNeighborhood} {
+		path, err := maze.ShortestPath(neighborhood)
+		if err != nil {
+			fmt.Printf("%d-neighborhood: %v\n", neighborhood, err)
+			continue
+		}
+		fmt.Printf("%d-neighborhood: %d moves\n", neighborhood, len(path)-1)
+		fmt.Print(maze.Render(path))
+	}
+}
+
+// exampleMaze is the warehouse floor solved when no maze file is given.
+const exampleMaze = `
+############
+#S.........#
+#.........##
+#..####....#
+#.....#....#
+######..#..#
+#.......#.G#
+############
+`
+
 func main() {
+	mazeFile := flag.String("maze", "", "solve the ASCII maze in this file ('#' wall, 'S' start, 'G' goal) and exit")
+	flag.Parse()
+
+	// Maze mode: solve a maze from a file.
+	if *mazeFile != "" {
+		text, err := os.ReadFile(*mazeFile)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		maze, err := ParseMaze(string(text))
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		solveMaze(maze)
+		return
+	}
+
 	// Create a new graph.
 	graph := NewBFSGraph()
 
@@ -157,4 +614,89 @@ func main() {
 		fmt.Print(component)
 	}
 	fmt.Println("]")
+
+	// Shortest-path tree and path reconstruction.
+	fmt.Println("\nShortest Paths:")
+	tree, err := graph.BFSTree(1)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if path, err := tree.PathTo(3); err == nil {
+		fmt.Println("Path from 1 to 3:", path)
+	}
+	if _, err := tree.PathTo(6); err != nil {
+		fmt.Println(err)
+	}
+
+	// Multi-source BFS: distances to the nearest of several start nodes on a path 10-11-...-20.
+	line := NewBFSGraph()
+	for node := 10; node < 20; node++ {
+		line.AddEdge(node, node+1)
+	}
+	forest, err := line.MultiSourceBFS([]int{10, 17})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("\nMulti-Source BFS from 10 and 17:")
+	for node := 10; node <= 20; node++ {
+		path, _ := forest.PathTo(node)
+		fmt.Printf("node %d: distance %d from source %d, path %v\n",
+			node, forest.Distances[node], forest.Sources[node], path)
+	}
+
+	// Bipartiteness: the first graph contains the triangle 6-7-8; an even cycle is bipartite.
+	fmt.Println("\nBipartiteness:")
+	if _, cycle := graph.IsBipartite(); cycle != nil {
+		fmt.Println("Not bipartite, odd cycle:", cycle)
+	}
+	square := NewBFSGraph()
+	square.AddEdge(1, 2)
+	square.AddEdge(2, 3)
+	square.AddEdge(3, 4)
+	square.AddEdge(4, 1)
+	if colors, cycle := square.IsBipartite(); cycle == nil {
+		fmt.Println("The 4-cycle is bipartite, colors:", colors[1], colors[2], colors[3], colors[4])
+	}
+	pentagon := NewBFSGraph()
+	for node := 1; node <= 5; node++ {
+		pentagon.AddEdge(node, node%5+1)
+	}
+	pentagon.AddEdge(1, 6)
+	pentagon.AddEdge(6, 7)
+	if _, cycle := pentagon.IsBipartite(); cycle != nil {
+		fmt.Println("The 5-cycle with a tail is not bipartite, odd cycle:", cycle)
+	}
+
+	// 0-1 BFS: free moves (weight 0) along 1-2-3-4 and paid moves (weight 1) elsewhere.
+	fmt.Println("\n0-1 BFS:")
+	weighted := NewBFSGraph()
+	for _, edge := range [][3]int{{1, 2, 0}, {2, 3, 0}, {3, 4, 0}, {1, 5, 1}, {5, 4, 1}, {4, 6, 1}, {1, 6, 1}} {
+		if err := weighted.AddWeightedEdge(edge[0], edge[1], edge[2]); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	zeroOne, err := weighted.ZeroOneBFS(1)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for node := 1; node <= 6; node++ {
+		path, _ := zeroOne.PathTo(node)
+		fmt.Printf("node %d: distance %d, path %v\n", node, zeroOne.Distances[node], path)
+	}
+	if err := weighted.AddWeightedEdge(1, 2, 5); err != nil {
+		fmt.Println(err)
+	}
+
+	// Grid front end: BFS over a maze without building an adjacency list.
+	fmt.Println("\nMaze:")
+	maze, err := ParseMaze(strings.TrimPrefix(exampleMaze, "\n"))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	solveMaze(maze)
 }
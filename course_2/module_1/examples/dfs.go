@@ -1,19 +1,34 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 )
 
 // DFSGraph represents a graph using an adjacency list.
+// Alongside it, every edge is kept once in an edge list, so that traversals can tell the two
+// directions of an undirected edge apart from two parallel edges.
 type DFSGraph struct {
 	adjacencyList map[int][]int
+	edges         []Edge        // Every edge added, indexed by edge ID.
+	incidence     map[int][]int // The IDs of the edges that can be followed out of each node.
+}
+
+// Edge is an edge as added to the graph.
+type Edge struct {
+	From     int
+	To       int
+	Directed bool
 }
 
 // NewDFSGraph creates and initializes a new DFSGraph.
 func NewDFSGraph() *DFSGraph {
 	return &DFSGraph{
 		adjacencyList: make(map[int][]int),
+		incidence:     make(map[int][]int),
 	}
 }
 
@@ -24,6 +39,197 @@ func (g *DFSGraph) AddEdge(u, v int, directed bool) {
 	if !directed {
 		g.adjacencyList[v] = append(g.adjacencyList[v], u)
 	}
+
+	id := len(g.edges)
+	g.edges = append(g.edges, Edge{From: u, To: v, Directed: directed})
+	g.incidence[u] = append(g.incidence[u], id)
+	if !directed && u != v {
+		g.incidence[v] = append(g.incidence[v], id)
+	} else if _, exists := g.incidence[v]; !exists {
+		g.incidence[v] = nil // Record v as a node of the graph.
+	}
+}
+
+// EdgeType classifies an edge by its place in a depth-first search forest.
+type EdgeType int
+
+const (
+	// TreeEdge leads to a newly discovered node and belongs to the DFS forest.
+	TreeEdge EdgeType = iota
+	// BackEdge leads to an ancestor that is still being explored; it closes a cycle.
+	BackEdge
+	// ForwardEdge leads to a descendant that has already been finished. Only directed edges can be forward edges.
+	ForwardEdge
+	// CrossEdge leads to a node in another, already finished subtree. Only directed edges can be cross edges.
+	CrossEdge
+)
+
+// String returns the name of the edge type.
+func (t EdgeType) String() string {
+	switch t {
+	case TreeEdge:
+		return "tree"
+	case BackEdge:
+		return "back"
+	case ForwardEdge:
+		return "forward"
+	case CrossEdge:
+		return "cross"
+	}
+	return fmt.Sprintf("EdgeType(%d)", int(t))
+}
+
+// ClassifiedEdge is an edge in the direction DFS followed it, with its type and edge ID.
+type ClassifiedEdge struct {
+	From int
+	To   int
+	ID   int
+	Type EdgeType
+}
+
+// DFSVisitor receives callbacks while Traverse runs. Any callback may be left nil.
+// Callbacks run in traversal order: Discover and Finish bracket the exploration of a node,
+// and Edge is called for every edge when DFS examines it, before a tree edge's target is discovered.
+type DFSVisitor struct {
+	StartTree func(root int)
+	Discover  func(node, time int)
+	Finish    func(node, time int)
+	Edge      func(edge ClassifiedEdge)
+}
+
+// DFSResult holds the discovery and finish times of a depth-first search, the DFS forest and the edge types.
+type DFSResult struct {
+	Discovery map[int]int      // The time each node was discovered.
+	Finish    map[int]int      // The time each node was finished.
+	Parents   map[int]int      // The parent of each node in the DFS forest; roots have none.
+	Order     []int            // The nodes in discovery order.
+	Edges     []ClassifiedEdge // Every edge in the order DFS examined it.
+}
+
+// dfsFrame is a node on the explicit DFS stack together with the position of the next edge to examine.
+type dfsFrame struct {
+	node  int
+	edges []ClassifiedEdge
+	next  int
+}
+
+// Traverse performs an iterative depth-first search that records discovery and finish times, the DFS forest
+// and the type of every edge, and reports each step to the visitor, which may be nil.
+// The search starts from the given roots in order, skipping those already reached; with no roots it
+// starts from every node in increasing order and so covers the whole graph. Neighbors are explored in
+// increasing order, matching DFS. Each undirected edge is examined once, from the side reached first,
+// and so is always a tree or back edge.
+func (g *DFSGraph) Traverse(visitor *DFSVisitor, roots ...int) *DFSResult {
+	if visitor == nil {
+		visitor = &DFSVisitor{}
+	}
+	if len(roots) == 0 {
+		roots = g.sortedNodes()
+	}
+
+	result := &DFSResult{
+		Discovery: make(map[int]int),
+		Finish:    make(map[int]int),
+		Parents:   make(map[int]int),
+	}
+	examined := make(map[int]bool) // Undirected edges already examined from their other end.
+	time := 0
+	var stack []dfsFrame
+
+	discover := func(node int) {
+		time++
+		result.Discovery[node] = time
+		result.Order = append(result.Order, node)
+		stack = append(stack, dfsFrame{node: node, edges: g.outgoingEdges(node)})
+		if visitor.Discover != nil {
+			visitor.Discover(node, time)
+		}
+	}
+
+	for _, root := range roots {
+		if _, discovered := result.Discovery[root]; discovered {
+			continue
+		}
+		if visitor.StartTree != nil {
+			visitor.StartTree(root)
+		}
+		discover(root)
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			// All edges examined: finish the node.
+			if top.next == len(top.edges) {
+				time++
+				result.Finish[top.node] = time
+				node := top.node
+				stack = stack[:len(stack)-1]
+				if visitor.Finish != nil {
+					visitor.Finish(node, time)
+				}
+				continue
+			}
+
+			edge := top.edges[top.next]
+			top.next++
+			if !g.edges[edge.ID].Directed {
+				if examined[edge.ID] {
+					continue
+				}
+				examined[edge.ID] = true
+			}
+
+			// Classify the edge by the state of its target: new, still open, or finished.
+			_, discovered := result.Discovery[edge.To]
+			_, finished := result.Finish[edge.To]
+			switch {
+			case !discovered:
+				edge.Type = TreeEdge
+			case !finished:
+				edge.Type = BackEdge
+			case result.Discovery[edge.From] < result.Discovery[edge.To]:
+				edge.Type = ForwardEdge
+			default:
+				edge.Type = CrossEdge
+			}
+			result.Edges = append(result.Edges, edge)
+			if visitor.Edge != nil {
+				visitor.Edge(edge)
+			}
+			if edge.Type == TreeEdge {
+				result.Parents[edge.To] = edge.From
+				discover(edge.To)
+			}
+		}
+	}
+
+	return result
+}
+
+// outgoingEdges returns the edges that can be followed out of a node, ordered by target and then by edge ID.
+func (g *DFSGraph) outgoingEdges(node int) []ClassifiedEdge {
+	edges := make([]ClassifiedEdge, 0, len(g.incidence[node]))
+	for _, id := range g.incidence[node] {
+		to := g.edges[id].To
+		if to == node {
+			to = g.edges[id].From
+		}
+		edges = append(edges, ClassifiedEdge{From: node, To: to, ID: id})
+	}
+	slices.SortFunc(edges, func(a, b ClassifiedEdge) int {
+		return cmp.Or(cmp.Compare(a.To, b.To), cmp.Compare(a.ID, b.ID))
+	})
+	return edges
+}
+
+// sortedNodes returns all nodes of the graph in increasing order.
+func (g *DFSGraph) sortedNodes() []int {
+	nodes := make([]int, 0, len(g.incidence))
+	for node := range g.incidence {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	return nodes
 }
 
 // DFS performs Depth-First Search starting from the given start node.
@@ -94,6 +300,237 @@ func (g *DFSGraph) ConnectedComponents() [][]int {
 	return components
 }
 
+// Component is a set of vertices together with the edges between them that belong to the component.
+type Component struct {
+	Vertices []int
+	Edges    []Edge
+}
+
+// connectivity holds the results of the low-link analysis of an undirected graph.
+type connectivity struct {
+	articulationPoints []int
+	bridges            []int // Edge IDs.
+	biconnected        [][]int
+	isolated           []int   // Vertices without edges, which form blocks of their own.
+	twoEdgeConnected   [][]int // Vertex sets.
+}
+
+// analyzeConnectivity runs one depth-first search with the low-link technique over the graph, treating
+// every edge as undirected. low[v] is the smallest discovery time reachable from v's subtree using at most
+// one back edge. For a tree edge (p, v):
+//   - it is a bridge if low[v] > discovery[p], since nothing below v reaches p or above;
+//   - p is an articulation point if low[v] >= discovery[p] and p is not a root; a root is one if it has
+//     two or more tree children;
+//   - the edges pushed since (p, v), when low[v] >= discovery[p], form a biconnected component;
+//   - the vertices pushed since v, when (p, v) is a bridge, form a 2-edge-connected component.
+func (g *DFSGraph) analyzeConnectivity() *connectivity {
+	// Build an undirected view with the same edge IDs.
+	view := NewDFSGraph()
+	for node := range g.incidence {
+		view.incidence[node] = nil
+	}
+	for _, edge := range g.edges {
+		view.AddEdge(edge.From, edge.To, false)
+	}
+
+	result := &connectivity{}
+	discovery := make(map[int]int)
+	low := make(map[int]int)
+	parentEdge := make(map[int]ClassifiedEdge)
+	rootChildren := 0
+	isArticulation := make(map[int]bool)
+	var edgeStack []int
+	var vertexStack []int
+	// The stack lengths when each vertex was discovered and its tree edge pushed, so that closing a
+	// component truncates the stacks in constant time instead of searching them.
+	edgeOffset := make(map[int]int)
+	vertexOffset := make(map[int]int)
+
+	visitor := &DFSVisitor{
+		StartTree: func(root int) {
+			rootChildren = 0
+		},
+		Discover: func(node, time int) {
+			discovery[node] = time
+			low[node] = time
+			vertexOffset[node] = len(vertexStack)
+			vertexStack = append(vertexStack, node)
+		},
+		Edge: func(edge ClassifiedEdge) {
+			if edge.Type == TreeEdge {
+				edgeOffset[edge.To] = len(edgeStack)
+			}
+			edgeStack = append(edgeStack, edge.ID)
+			switch edge.Type {
+			case TreeEdge:
+				parentEdge[edge.To] = edge
+			case BackEdge:
+				low[edge.From] = min(low[edge.From], discovery[edge.To])
+			}
+		},
+		Finish: func(node, time int) {
+			tree, hasParent := parentEdge[node]
+			if !hasParent {
+				// A root: its remaining vertices form the last 2-edge-connected component of the tree.
+				if rootChildren >= 2 {
+					isArticulation[node] = true
+				}
+				if len(view.incidence[node]) == 0 {
+					result.isolated = append(result.isolated, node)
+				}
+				result.twoEdgeConnected = append(result.twoEdgeConnected, vertexStack)
+				vertexStack = nil
+				return
+			}
+
+			parent := tree.From
+			low[parent] = min(low[parent], low[node])
+			if _, parentIsChild := parentEdge[parent]; !parentIsChild {
+				rootChildren++
+			}
+
+			if low[node] >= discovery[parent] {
+				if _, parentIsChild := parentEdge[parent]; parentIsChild {
+					isArticulation[parent] = true
+				}
+				i := edgeOffset[node]
+				result.biconnected = append(result.biconnected, slices.Clone(edgeStack[i:]))
+				edgeStack = edgeStack[:i]
+			}
+			if low[node] > discovery[parent] {
+				result.bridges = append(result.bridges, tree.ID)
+				i := vertexOffset[node]
+				result.twoEdgeConnected = append(result.twoEdgeConnected, slices.Clone(vertexStack[i:]))
+				vertexStack = vertexStack[:i]
+			}
+		},
+	}
+	view.Traverse(visitor)
+
+	for node := range isArticulation {
+		result.articulationPoints = append(result.articulationPoints, node)
+	}
+	slices.Sort(result.articulationPoints)
+	return result
+}
+
+// ArticulationPoints returns the vertices whose removal disconnects their connected component,
+// treating every edge as undirected.
+func (g *DFSGraph) ArticulationPoints() []int {
+	return g.analyzeConnectivity().articulationPoints
+}
+
+// Bridges returns the edges whose removal disconnects their connected component, treating every edge as
+// undirected. Parallel edges are never bridges.
+func (g *DFSGraph) Bridges() []Edge {
+	ids := g.analyzeConnectivity().bridges
+	slices.Sort(ids)
+	bridges := make([]Edge, len(ids))
+	for i, id := range ids {
+		bridges[i] = g.edges[id]
+	}
+	return bridges
+}
+
+// BiconnectedComponents returns the blocks of the graph, treating every edge as undirected: the maximal
+// sets of edges in which every two edges lie on a common simple cycle, or single edges that lie on none,
+// with their vertices. Blocks share articulation points, and a vertex without edges is a block by itself.
+func (g *DFSGraph) BiconnectedComponents() []Component {
+	analysis := g.analyzeConnectivity()
+	components := make([]Component, 0, len(analysis.biconnected)+len(analysis.isolated))
+	for _, ids := range analysis.biconnected {
+		components = append(components, g.componentFromEdges(ids))
+	}
+	for _, vertex := range analysis.isolated {
+		components = append(components, Component{Vertices: []int{vertex}})
+	}
+	sortComponents(components)
+	return components
+}
+
+// TwoEdgeConnectedComponents returns the components left after removing every bridge, treating every edge
+// as undirected: within one, every two vertices are joined by two edge-disjoint paths. Each component comes
+// with its edges, which are all edges of the graph except the bridges.
+func (g *DFSGraph) TwoEdgeConnectedComponents() []Component {
+	analysis := g.analyzeConnectivity()
+	componentOf := make(map[int]int)
+	components := make([]Component, len(analysis.twoEdgeConnected))
+	for i, vertices := range analysis.twoEdgeConnected {
+		components[i].Vertices = slices.Sorted(slices.Values(vertices))
+		for _, vertex := range vertices {
+			componentOf[vertex] = i
+		}
+	}
+	for _, edge := range g.edges {
+		// Bridges are the only edges whose ends lie in different components.
+		if i := componentOf[edge.From]; i == componentOf[edge.To] {
+			components[i].Edges = append(components[i].Edges, edge)
+		}
+	}
+	sortComponents(components)
+	return components
+}
+
+// componentFromEdges builds a component from edge IDs, listing the edges by ID and the vertices in order.
+func (g *DFSGraph) componentFromEdges(ids []int) Component {
+	ids = slices.Sorted(slices.Values(ids))
+	var component Component
+	for _, id := range ids {
+		edge := g.edges[id]
+		component.Edges = append(component.Edges, edge)
+		component.Vertices = append(component.Vertices, edge.From, edge.To)
+	}
+	slices.Sort(component.Vertices)
+	component.Vertices = slices.Compact(component.Vertices)
+	return component
+}
+
+// sortComponents orders components by their vertex lists.
+func sortComponents(components []Component) {
+	slices.SortFunc(components, func(a, b Component) int {
+		return slices.Compare(a.Vertices, b.Vertices)
+	})
+}
+
+// formatEdges formats edges as u-v pairs.
+func formatEdges(edges []Edge) string {
+	parts := make([]string, len(edges))
+	for i, edge := range edges {
+		parts[i] = fmt.Sprintf("%d-%d", edge.From, edge.To)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// countComponents counts the connected components of the vertices 1..n, skipping one vertex and one edge
+// (-1 skips none), with union-find. It is the brute-force reference for articulation points and bridges.
+func countComponents(n int, edges [][2]int, skipVertex, skipEdge int) int {
+	parent := make([]int, n+1)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(x int) int
+	find = func(x int) int {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	components := n
+	if skipVertex > 0 {
+		components--
+	}
+	for i, edge := range edges {
+		if i == skipEdge || edge[0] == skipVertex || edge[1] == skipVertex {
+			continue
+		}
+		if a, b := find(edge[0]), find(edge[1]); a != b {
+			parent[a] = b
+			components--
+		}
+	}
+	return components
+}
+
 func main() {
 	graph := NewDFSGraph()
 	graph.AddEdge(1, 2, false)
@@ -115,4 +552,106 @@ func main() {
 		sort.Ints(component) // Sort each component for consistent output.
 		fmt.Printf("Component %d: %v\n", i+1, component)
 	}
+
+	// Timestamps and edge types on the directed graph from CLRS, with u..z numbered 1..6.
+	names := map[int]string{1: "u", 2: "v", 3: "w", 4: "x", 5: "y", 6: "z"}
+	directed := NewDFSGraph()
+	for _, edge := range [][2]int{{1, 2}, {1, 4}, {4, 2}, {2, 5}, {5, 4}, {3, 5}, {3, 6}, {6, 6}} {
+		directed.AddEdge(edge[0], edge[1], true)
+	}
+
+	// A visitor prints the parenthesis structure of the discovery and finish times.
+	var parentheses strings.Builder
+	visitor := &DFSVisitor{
+		Discover: func(node, time int) { fmt.Fprintf(&parentheses, "(%s ", names[node]) },
+		Finish:   func(node, time int) { fmt.Fprintf(&parentheses, "%s) ", names[node]) },
+	}
+	result := directed.Traverse(visitor)
+
+	fmt.Println("\nDiscovery/Finish Times:")
+	for _, node := range result.Order {
+		fmt.Printf("%s: %d/%d\n", names[node], result.Discovery[node], result.Finish[node])
+	}
+	fmt.Println("Parenthesis structure:", strings.TrimSpace(parentheses.String()))
+	fmt.Println("Edge types:")
+	for _, edge := range result.Edges {
+		fmt.Printf("%s -> %s: %s\n", names[edge.From], names[edge.To], edge.Type)
+	}
+
+	// In an undirected graph every edge is a tree or a back edge.
+	undirected := graph.Traverse(nil)
+	fmt.Println("\nUndirected edge types:")
+	for _, edge := range undirected.Edges {
+		fmt.Printf("%d - %d: %s\n", edge.From, edge.To, edge.Type)
+	}
+
+	// Single points of failure in a network: two rings joined at router 5, a ring hanging off a link
+	// from 3 to 4, a leaf 9 and a doubled link from 9 to 10.
+	network := NewDFSGraph()
+	for _, edge := range [][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {4, 5}, {5, 6}, {6, 4}, {5, 7}, {7, 8}, {8, 5}, {6, 9}, {9, 10}, {9, 10}} {
+		network.AddEdge(edge[0], edge[1], false)
+	}
+	fmt.Println("\nArticulation points:", network.ArticulationPoints())
+	fmt.Println("Bridges:", formatEdges(network.Bridges()))
+	fmt.Println("Biconnected components:")
+	for _, component := range network.BiconnectedComponents() {
+		fmt.Printf("  vertices %v, edges %s\n", component.Vertices, formatEdges(component.Edges))
+	}
+	fmt.Println("2-edge-connected components:")
+	for _, component := range network.TwoEdgeConnectedComponents() {
+		fmt.Printf("  vertices %v, edges %s\n", component.Vertices, formatEdges(component.Edges))
+	}
+
+	// Check articulation points and bridges against removing each vertex and edge on random graphs.
+	matches := true
+	for trial := 0; trial < 200; trial++ {
+		n := 2 + trial%12
+		random := NewDFSGraph()
+		var edges [][2]int
+		for i := 0; i < n+trial%7; i++ {
+			u, v := 1+(trial*7+i*13)%n, 1+(trial*3+i*i*5+1)%n
+			if u != v {
+				random.AddEdge(u, v, false)
+				edges = append(edges, [2]int{u, v})
+			}
+		}
+		for v := 1; v <= n; v++ {
+			if _, exists := random.incidence[v]; !exists {
+				random.incidence[v] = nil // Add isolated vertices.
+			}
+		}
+
+		before := countComponents(n, edges, -1, -1)
+		var expectedPoints []int
+		for v := 1; v <= n; v++ {
+			if len(random.incidence[v]) > 0 && countComponents(n, edges, v, -1) > before {
+				expectedPoints = append(expectedPoints, v)
+			}
+		}
+		var expectedBridges []Edge
+		for i, edge := range edges {
+			if countComponents(n, edges, -1, i) > before {
+				expectedBridges = append(expectedBridges, Edge{From: edge[0], To: edge[1]})
+			}
+		}
+		matches = matches && slices.Equal(random.ArticulationPoints(), expectedPoints) &&
+			slices.Equal(random.Bridges(), expectedBridges)
+	}
+	fmt.Println("Articulation points and bridges match brute force:", matches)
+
+	// A directed edge counts as undirected: both of its ends are in one block, and neither is isolated.
+	oneWay := NewDFSGraph()
+	oneWay.AddEdge(2, 1, true)
+	blocks := oneWay.BiconnectedComponents()
+	fmt.Println("Blocks of a single directed edge are one block:", len(blocks) == 1 && slices.Equal(blocks[0].Vertices, []int{1, 2}))
+
+	// On a long path every edge is a bridge and every inner vertex an articulation point; closing each
+	// component in constant time keeps this linear.
+	const pathLength = 200000
+	path := NewDFSGraph()
+	for v := 1; v < pathLength; v++ {
+		path.AddEdge(v, v+1, false)
+	}
+	fmt.Printf("Path of %d vertices: %d bridges, %d articulation points, %d blocks\n", pathLength,
+		len(path.Bridges()), len(path.ArticulationPoints()), len(path.BiconnectedComponents()))
 }
@@ -1,45 +1,130 @@
 package main
 
 import (
+	"container/heap"
+	"encoding/csv"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"iter"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // TSGraph represents a directed graph using an adjacency list.
+// As a weighted DAG, every node has a duration and every edge a weight (a lag between the end of one
+// node and the start of the next); both default to 0. weights[u][i] belongs to the edge to adjacencyList[u][i].
 type TSGraph struct {
 	adjacencyList map[int][]int
+	weights       map[int][]float64
+	durations     map[int]float64
 }
 
 // NewTSGraph creates and initializes a new TSGraph.
 func NewTSGraph() *TSGraph {
 	return &TSGraph{
 		adjacencyList: make(map[int][]int),
+		weights:       make(map[int][]float64),
+		durations:     make(map[int]float64),
 	}
 }
 
 // AddEdge adds a directed edge from node u to node v.
 func (g *TSGraph) AddEdge(u, v int) {
+	g.AddWeightedEdge(u, v, 0)
+}
+
+// AddWeightedEdge adds a directed edge from node u to node v with the given weight.
+// A positive weight is a delay between the end of u and the start of v; a negative weight lets v start early.
+func (g *TSGraph) AddWeightedEdge(u, v int, weight float64) {
 	g.adjacencyList[u] = append(g.adjacencyList[u], v)
+	g.weights[u] = append(g.weights[u], weight)
+}
+
+// SetDuration sets the duration of a node, adding the node if it has no edges yet.
+func (g *TSGraph) SetDuration(node int, duration float64) error {
+	if duration < 0 || math.IsNaN(duration) || math.IsInf(duration, 0) {
+		return fmt.Errorf("duration %v of node %d is not a finite non-negative number", duration, node)
+	}
+	g.durations[node] = duration
+	return nil
+}
+
+// CycleError is returned when the graph is not a DAG. Cycle lists the nodes of a directed cycle in order;
+// the last node has an edge back to the first.
+type CycleError struct {
+	Cycle []int
+}
+
+// Error describes the cycle, e.g. "graph contains a cycle, topological sort not possible: 1 -> 2 -> 1".
+func (e *CycleError) Error() string {
+	nodes := make([]string, 0, len(e.Cycle)+1)
+	for _, node := range append(e.Cycle, e.Cycle[0]) {
+		nodes = append(nodes, fmt.Sprint(node))
+	}
+	return "graph contains a cycle, topological sort not possible: " + strings.Join(nodes, " -> ")
+}
+
+// nodes returns every node of the graph, including nodes that only have incoming edges, in increasing order.
+func (g *TSGraph) nodes() []int {
+	seen := make(map[int]bool)
+	for node := range g.durations {
+		seen[node] = true
+	}
+	for node, neighbors := range g.adjacencyList {
+		seen[node] = true
+		for _, neighbor := range neighbors {
+			seen[neighbor] = true
+		}
+	}
+	nodes := make([]int, 0, len(seen))
+	for node := range seen {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// inDegrees returns the number of incoming edges of every node.
+func (g *TSGraph) inDegrees() map[int]int {
+	inDegree := make(map[int]int)
+	for _, node := range g.nodes() {
+		inDegree[node] += 0
+		for _, neighbor := range g.adjacencyList[node] {
+			inDegree[neighbor]++
+		}
+	}
+	return inDegree
 }
 
 // TopologicalSort performs a topological sort on the graph.
+// If the graph contains a cycle, it returns a *CycleError holding one.
 func (g *TSGraph) TopologicalSort() ([]int, error) {
 	visited := make(map[int]bool)  // Track permanently visited nodes.
 	tempMark := make(map[int]bool) // Track temporarily marked nodes for cycle detection.
 	result := make([]int, 0)       // Store topological ordering.
+	path := make([]int, 0)         // The temporarily marked nodes, in the order they were entered.
 
 	var dfs func(node int) error
 	dfs = func(node int) error {
 		if tempMark[node] {
-			return errors.New("graph contains a cycle, topological sort not possible")
+			// The node is on the current path, so the path from it onwards closes a cycle.
+			i := slices.Index(path, node)
+			return &CycleError{Cycle: slices.Clone(path[i:])}
 		}
 		if !visited[node] {
 			tempMark[node] = true // Mark the node temporarily.
+			path = append(path, node)
 			for _, neighbor := range g.adjacencyList[node] {
 				if err := dfs(neighbor); err != nil {
 					return err
 				}
 			}
+			path = path[:len(path)-1]
 			tempMark[node] = false        // Remove the temporary mark.
 			visited[node] = true          // Mark the node as permanently visited.
 			result = append(result, node) // Add the node to the result.
@@ -48,7 +133,7 @@ func (g *TSGraph) TopologicalSort() ([]int, error) {
 	}
 
 	// Perform DFS for all nodes in the graph.
-	for node := range g.adjacencyList {
+	for _, node := range g.nodes() {
 		if !visited[node] {
 			if err := dfs(node); err != nil {
 				return nil, err
@@ -64,7 +149,369 @@ func (g *TSGraph) TopologicalSort() ([]int, error) {
 	return result, nil
 }
 
+// intHeap is a min-heap of nodes.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	node := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return node
+}
+
+// LexicographicTopologicalSort performs a topological sort with Kahn's algorithm, always taking the
+// smallest node without remaining incoming edges next. The result is the lexicographically smallest
+// topological order. It runs in O(V log V + E) time: every node enters and leaves the heap once.
+// If the graph contains a cycle, it returns a *CycleError holding one.
+func (g *TSGraph) LexicographicTopologicalSort() ([]int, error) {
+	inDegree := g.inDegrees()
+	ready := &intHeap{}
+	for node, degree := range inDegree {
+		if degree == 0 {
+			heap.Push(ready, node)
+		}
+	}
+
+	result := make([]int, 0, len(inDegree))
+	for ready.Len() > 0 {
+		node := heap.Pop(ready).(int)
+		result = append(result, node)
+		for _, neighbor := range g.adjacencyList[node] {
+			inDegree[neighbor]--
+			if inDegree[neighbor] == 0 {
+				heap.Push(ready, neighbor)
+			}
+		}
+	}
+
+	// Nodes left with incoming edges lie on or behind a cycle; the DFS sort reports one.
+	if len(result) < len(inDegree) {
+		_, err := g.TopologicalSort()
+		return nil, err
+	}
+	return result, nil
+}
+
+// AllTopologicalSorts returns an iterator over every topological order of the graph, in lexicographic order.
+// A DAG can have up to V! topological orders, so this is meant for small graphs; stop the iteration early to
+// bound the work. Each order is a fresh slice. If the graph contains a cycle, it returns a *CycleError instead.
+func (g *TSGraph) AllTopologicalSorts() (iter.Seq[[]int], error) {
+	if _, err := g.TopologicalSort(); err != nil {
+		return nil, err
+	}
+
+	return func(yield func([]int) bool) {
+		inDegree := g.inDegrees()
+		nodes := g.nodes()
+		placed := make(map[int]bool)
+		order := make([]int, 0, len(nodes))
+
+		// Backtracking: try every node without remaining incoming edges at the next position.
+		var extend func() bool
+		extend = func() bool {
+			if len(order) == len(nodes) {
+				return yield(slices.Clone(order))
+			}
+			for _, node := range nodes {
+				if placed[node] || inDegree[node] != 0 {
+					continue
+				}
+				placed[node] = true
+				order = append(order, node)
+				for _, neighbor := range g.adjacencyList[node] {
+					inDegree[neighbor]--
+				}
+
+				more := extend()
+
+				for _, neighbor := range g.adjacencyList[node] {
+					inDegree[neighbor]++
+				}
+				order = order[:len(order)-1]
+				placed[node] = false
+				if !more {
+					return false
+				}
+			}
+			return true
+		}
+		extend()
+	}, nil
+}
+
+// Schedule is the result of the critical path method on a DAG of tasks, where node durations are
+// task durations and edges are "must finish before" dependencies. All times are relative to the project start.
+type Schedule struct {
+	EarliestStart  map[int]float64 // The earliest time each task can start.
+	EarliestFinish map[int]float64 // EarliestStart plus the duration.
+	LatestStart    map[int]float64 // The latest time each task can start without delaying the project.
+	LatestFinish   map[int]float64 // LatestStart plus the duration.
+	Slack          map[int]float64 // How long each task can be delayed: LatestStart - EarliestStart.
+	Makespan       float64         // The length of the project, i.e. of the critical path.
+	CriticalPath   []int           // A longest path; every task on it has zero slack.
+	Order          []int           // The tasks in the lexicographically smallest topological order.
+}
+
+// criticalTolerance absorbs floating-point rounding when deciding whether a task has zero slack.
+const criticalTolerance = 1e-9
+
+// IsCritical reports whether the task has no slack, so delaying it delays the whole project.
+func (s *Schedule) IsCritical(node int) bool {
+	return s.Slack[node] <= criticalTolerance
+}
+
+// forwardPass computes the earliest start and finish of every node in topological order. The earliest
+// start is 0 or, if later, the latest earliest finish of a predecessor plus the edge weight; parents
+// records that predecessor so the longest path can be rebuilt. The order is the lexicographic one.
+func (g *TSGraph) forwardPass() ([]int, map[int]float64, map[int]float64, map[int]int, error) {
+	order, err := g.LexicographicTopologicalSort()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	earliestStart := make(map[int]float64, len(order))
+	earliestFinish := make(map[int]float64, len(order))
+	parents := make(map[int]int)
+	for _, node := range order {
+		earliestStart[node] += 0
+		earliestFinish[node] = earliestStart[node] + g.durations[node]
+		for i, neighbor := range g.adjacencyList[node] {
+			if start := earliestFinish[node] + g.weights[node][i]; start > earliestStart[neighbor] {
+				earliestStart[neighbor] = start
+				parents[neighbor] = node
+			}
+		}
+	}
+	return order, earliestStart, earliestFinish, parents, nil
+}
+
+// longestPath rebuilds a heaviest path from the results of forwardPass: it ends at the node that finishes
+// last and is followed back through the parents. It returns the path and its length.
+func longestPath(order []int, earliestFinish map[int]float64, parents map[int]int) ([]int, float64) {
+	if len(order) == 0 {
+		return nil, 0
+	}
+	end := order[0]
+	for _, node := range order {
+		if earliestFinish[node] > earliestFinish[end] {
+			end = node
+		}
+	}
+	path := []int{end}
+	for node, ok := parents[end]; ok; node, ok = parents[node] {
+		path = append(path, node)
+	}
+	slices.Reverse(path)
+	return path, earliestFinish[end]
+}
+
+// LongestPath finds a heaviest path in the DAG in O(V log V + E) time; the log factor only comes from
+// visiting the nodes in lexicographic topological order, which makes the choice among equally heavy paths
+// reproducible. The length of a path is the sum of the durations of its nodes and the weights of its edges;
+// a path may start and end at any node.
+// If the graph contains a cycle, it returns a *CycleError holding one.
+func (g *TSGraph) LongestPath() ([]int, float64, error) {
+	order, _, earliestFinish, parents, err := g.forwardPass()
+	if err != nil {
+		return nil, 0, err
+	}
+	path, length := longestPath(order, earliestFinish, parents)
+	return path, length, nil
+}
+
+// CriticalPath schedules the DAG of tasks with the critical path method in O(V log V + E) time, like LongestPath.
+// A forward pass in topological order gives the earliest start times and the project length; a backward
+// pass in reverse order gives the latest start times that still meet it. Tasks without slack are critical.
+// If the graph contains a cycle, it returns a *CycleError holding one.
+func (g *TSGraph) CriticalPath() (*Schedule, error) {
+	order, earliestStart, earliestFinish, parents, err := g.forwardPass()
+	if err != nil {
+		return nil, err
+	}
+	path, makespan := longestPath(order, earliestFinish, parents)
+
+	schedule := &Schedule{
+		EarliestStart:  earliestStart,
+		EarliestFinish: earliestFinish,
+		LatestStart:    make(map[int]float64, len(order)),
+		LatestFinish:   make(map[int]float64, len(order)),
+		Slack:          make(map[int]float64, len(order)),
+		Makespan:       makespan,
+		CriticalPath:   path,
+		Order:          order,
+	}
+	for _, node := range slices.Backward(order) {
+		latestFinish := makespan
+		for i, neighbor := range g.adjacencyList[node] {
+			latestFinish = min(latestFinish, schedule.LatestStart[neighbor]-g.weights[node][i])
+		}
+		schedule.LatestFinish[node] = latestFinish
+		schedule.LatestStart[node] = latestFinish - g.durations[node]
+		schedule.Slack[node] = schedule.LatestStart[node] - earliestStart[node]
+	}
+	return schedule, nil
+}
+
+// ParseTasks reads a project plan in CSV form, one task per record:
+//
+//	task,duration,depends-on
+//	compile,3,fetch
+//	link,1,compile;codegen
+//
+// The header line is optional: a first record named "task" is taken as the header only if its duration
+// is not a number, so a real task called "task" is kept. Lines starting with '#' are comments, and
+// dependencies are separated by ';' or given as further fields. Tasks may depend on tasks listed later.
+// Task i of the plan becomes node i of the graph, with an edge from each dependency to the task; the
+// returned names are indexed by node.
+func ParseTasks(r io.Reader) (*TSGraph, []string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	graph := NewTSGraph()
+	names := make([]string, 0)
+	ids := make(map[string]int)
+	type dependency struct {
+		name string
+		task int
+		line int
+	}
+	dependencies := make([]dependency, 0)
+	first := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		name := strings.TrimSpace(record[0])
+		if first {
+			first = false
+			// A header names its columns, so its duration field is not a number.
+			if len(record) >= 2 && strings.EqualFold(name, "task") {
+				if _, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64); err != nil {
+					continue
+				}
+			}
+		}
+		if len(record) < 2 {
+			return nil, nil, fmt.Errorf("line %d: expected task,duration,depends-on", line)
+		}
+		if name == "" {
+			return nil, nil, fmt.Errorf("line %d: task name is empty", line)
+		}
+		if _, ok := ids[name]; ok {
+			return nil, nil, fmt.Errorf("line %d: task %q is listed twice", line, name)
+		}
+		duration, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: invalid duration %q for task %q", line, record[1], name)
+		}
+
+		task := len(names)
+		if err := graph.SetDuration(task, duration); err != nil {
+			return nil, nil, fmt.Errorf("line %d: task %q: %v", line, name, err)
+		}
+		ids[name] = task
+		names = append(names, name)
+		for _, field := range record[2:] {
+			for _, dep := range strings.Split(field, ";") {
+				if dep = strings.TrimSpace(dep); dep != "" {
+					dependencies = append(dependencies, dependency{dep, task, line})
+				}
+			}
+		}
+	}
+
+	// Dependencies are resolved after all tasks are known.
+	for _, dep := range dependencies {
+		from, ok := ids[dep.name]
+		if !ok {
+			return nil, nil, fmt.Errorf("line %d: task %q depends on unknown task %q", dep.line, names[dep.task], dep.name)
+		}
+		graph.AddEdge(from, dep.task)
+	}
+	return graph, names, nil
+}
+
+// taskNames maps node IDs to task names.
+func taskNames(nodes []int, names []string) []string {
+	result := make([]string, len(nodes))
+	for i, node := range nodes {
+		result[i] = names[node]
+	}
+	return result
+}
+
+// planProject prints the critical path schedule of a project plan in the ParseTasks format.
+func planProject(r io.Reader) error {
+	graph, names, err := ParseTasks(r)
+	if err != nil {
+		return err
+	}
+	schedule, err := graph.CriticalPath()
+	var cycleErr *CycleError
+	if errors.As(err, &cycleErr) {
+		return fmt.Errorf("tasks depend on each other in a cycle: %s", strings.Join(taskNames(cycleErr.Cycle, names), " -> "))
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-12s %8s %8s %8s %8s %8s %8s\n", "Task", "Duration", "ES", "EF", "LS", "LF", "Slack")
+	for _, node := range schedule.Order {
+		marker := ""
+		if schedule.IsCritical(node) {
+			marker = " *"
+		}
+		fmt.Printf("%-12s %8g %8g %8g %8g %8g %8g%s\n", names[node], graph.durations[node],
+			schedule.EarliestStart[node], schedule.EarliestFinish[node],
+			schedule.LatestStart[node], schedule.LatestFinish[node], schedule.Slack[node], marker)
+	}
+	fmt.Println("Project length:", schedule.Makespan)
+	fmt.Println("Critical path:", strings.Join(taskNames(schedule.CriticalPath, names), " -> "))
+	return nil
+}
+
+// exampleTasks is the build pipeline planned when no task file is given.
+const exampleTasks = `task,duration,depends-on
+fetch,2,
+codegen,4,fetch
+compile,6,fetch
+lint,1,fetch
+test,5,compile;codegen
+package,2,compile
+docs,3,codegen
+release,1,test;package;lint;docs
+`
+
 func main() {
+	tasksFile := flag.String("tasks", "", "print the critical path schedule of the task,duration,depends-on CSV in this file and exit")
+	flag.Parse()
+
+	// Planning mode: schedule a project from a file.
+	if *tasksFile != "" {
+		file, err := os.Open(*tasksFile)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		if err := planProject(file); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Example usage of TSGraph.
 	graph := NewTSGraph()
 	graph.AddEdge(5, 2)
@@ -80,4 +527,62 @@ func main() {
 	} else {
 		fmt.Println("Topological Order:", order)
 	}
+
+	// Kahn's algorithm with a min-heap gives the lexicographically smallest order.
+	if order, err := graph.LexicographicTopologicalSort(); err != nil {
+		fmt.Println("Error:", err)
+	} else {
+		fmt.Println("Lexicographically Smallest Order:", order)
+	}
+
+	// Every topological order of the DAG.
+	orders, err := graph.AllTopologicalSorts()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	count := 0
+	for order := range orders {
+		count++
+		fmt.Printf("Order %d: %v\n", count, order)
+	}
+
+	// A cyclic graph: the error carries the cycle.
+	cyclic := NewTSGraph()
+	cyclic.AddEdge(1, 2)
+	cyclic.AddEdge(2, 3)
+	cyclic.AddEdge(3, 4)
+	cyclic.AddEdge(4, 2)
+	cyclic.AddEdge(4, 5)
+	_, err = cyclic.LexicographicTopologicalSort()
+	var cycleErr *CycleError
+	if errors.As(err, &cycleErr) {
+		fmt.Println("Error:", err)
+		fmt.Println("Cycle:", cycleErr.Cycle)
+	}
+
+	// Longest path in a weighted DAG: node durations plus edge weights.
+	weighted := NewTSGraph()
+	for node, duration := range []float64{3, 2, 4, 1, 2} {
+		if err := weighted.SetDuration(node, duration); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	}
+	weighted.AddWeightedEdge(0, 1, 1)
+	weighted.AddWeightedEdge(0, 2, 0)
+	weighted.AddWeightedEdge(1, 3, 5)
+	weighted.AddWeightedEdge(2, 3, 0)
+	weighted.AddWeightedEdge(2, 4, 2)
+	if path, length, err := weighted.LongestPath(); err != nil {
+		fmt.Println("Error:", err)
+	} else {
+		fmt.Printf("Longest Path: %v (length %g)\n", path, length)
+	}
+
+	// Critical path method on a build pipeline.
+	fmt.Println()
+	if err := planProject(strings.NewReader(exampleTasks)); err != nil {
+		fmt.Println("Error:", err)
+	}
 }
@@ -2,17 +2,22 @@ package main
 
 import (
 	"bufio"
+	"container/heap"
+	"errors"
+	"flag"
 	"fmt"
+	"math/rand"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
 )
 
-// Graph structure to represent a directed graph.
+// Graph structure to represent a directed graph on the vertices 1..maxVertex.
 type Graph struct {
-	adj    map[int][]int
-	revAdj map[int][]int
+	adj       map[int][]int
+	revAdj    map[int][]int
+	maxVertex int
 }
 
 func newGraph() *Graph {
@@ -26,67 +31,244 @@ func newGraph() *Graph {
 func (g *Graph) addEdge(from, to int) {
 	g.adj[from] = append(g.adj[from], to)
 	g.revAdj[to] = append(g.revAdj[to], from)
+	g.maxVertex = max(g.maxVertex, from, to)
 }
 
-// Performs DFS on the graph and fills the stack with the visited nodes.
-func (g *Graph) dfs(v int, visited []bool, stack *[]int) {
-	visited[v] = true
-	for _, neighbor := range g.adj[v] {
-		if !visited[neighbor] {
-			g.dfs(neighbor, visited, stack)
+// SCC holds the strongly connected components of a graph. Components are labelled 1..k in a topological
+// order of the condensation: every edge between two components goes from a smaller to a larger label.
+type SCC struct {
+	Membership []int   // Membership[v] is the label of the component of vertex v; Membership[0] is unused.
+	Components [][]int // Components[c] lists the vertices of component c in increasing order; Components[0] is unused.
+}
+
+// newSCC builds an SCC from a component label for every vertex.
+func newSCC(membership []int, count int) *SCC {
+	components := make([][]int, count+1)
+	for v := 1; v < len(membership); v++ {
+		components[membership[v]] = append(components[membership[v]], v)
+	}
+	return &SCC{Membership: membership, Components: components}
+}
+
+// Count returns the number of strongly connected components.
+func (s *SCC) Count() int {
+	return len(s.Components) - 1
+}
+
+// Sizes returns the sizes of the components in descending order.
+func (s *SCC) Sizes() []int {
+	sizes := make([]int, 0, s.Count())
+	for _, component := range s.Components[1:] {
+		sizes = append(sizes, len(component))
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	return sizes
+}
+
+// Condensation returns the DAG with one vertex per component and an edge c -> d whenever an edge of g
+// leads from component c to component d. Parallel edges are merged, so it has at most E edges.
+// The result is a Graph on the labels 1..k and can be passed to TopologicalSort.
+func (s *SCC) Condensation(g *Graph) *Graph {
+	condensation := newGraph()
+	condensation.maxVertex = s.Count()
+	lastSeen := make([]int, s.Count()+1) // lastSeen[d] == c once the edge c -> d has been added.
+	for c := 1; c <= s.Count(); c++ {
+		for _, v := range s.Components[c] {
+			for _, w := range g.adj[v] {
+				if d := s.Membership[w]; d != c && lastSeen[d] != c {
+					lastSeen[d] = c
+					condensation.addEdge(c, d)
+				}
+			}
 		}
 	}
-	*stack = append(*stack, v)
+	return condensation
 }
 
-// Performs reverse DFS on the reversed graph and returns the size of the SCC.
-func (g *Graph) reverseDFS(v int, visited []bool) int {
-	visited[v] = true
-	size := 1
-	for _, neighbor := range g.revAdj[v] {
-		if !visited[neighbor] {
-			size += g.reverseDFS(neighbor, visited)
+// Kosaraju finds the strongly connected components with two iterative depth-first passes, in O(V + E) time.
+// The first pass on the graph records the vertices by finishing time; the second pass on the reversed
+// graph, in decreasing finishing time, collects one component per search. The components are found
+// in topological order of the condensation, so their labels need no renumbering.
+func (g *Graph) Kosaraju() *SCC {
+	n := g.maxVertex
+	visited := make([]bool, n+1)
+	order := make([]int, 0, n) // Vertices by increasing finishing time.
+
+	// First pass: an explicit stack of (vertex, index of the next edge) replaces the recursion.
+	type frame struct {
+		vertex, next int
+	}
+	stack := make([]frame, 0)
+	for root := 1; root <= n; root++ {
+		if visited[root] {
+			continue
+		}
+		visited[root] = true
+		stack = append(stack, frame{root, 0})
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.next < len(g.adj[top.vertex]) {
+				neighbor := g.adj[top.vertex][top.next]
+				top.next++
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					stack = append(stack, frame{neighbor, 0})
+				}
+				continue
+			}
+			order = append(order, top.vertex)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	// Second pass: the order of visits within a component does not matter, so a plain stack suffices.
+	membership := make([]int, n+1)
+	count := 0
+	pending := make([]int, 0)
+	for i := len(order) - 1; i >= 0; i-- {
+		if membership[order[i]] != 0 {
+			continue
+		}
+		count++
+		membership[order[i]] = count
+		pending = append(pending, order[i])
+		for len(pending) > 0 {
+			v := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			for _, neighbor := range g.revAdj[v] {
+				if membership[neighbor] == 0 {
+					membership[neighbor] = count
+					pending = append(pending, neighbor)
+				}
+			}
 		}
 	}
-	return size
+	return newSCC(membership, count)
 }
 
-// Implements Kosaraju's algorithm to find SCCs.
-func (g *Graph) kosaraju(maxVertex int) []int {
-	stack := make([]int, 0) // Use make() instead of []int{}.
-	visited := make([]bool, maxVertex+1)
+// Tarjan finds the strongly connected components with a single iterative depth-first search, in O(V + E) time.
+// Each vertex gets a discovery index and a low-link, the smallest index reachable through its subtree and
+// one edge back into a vertex still on the component stack; a vertex whose low-link equals its own index
+// is the root of a component, which is popped off the component stack. Components complete in reverse
+// topological order of the condensation and are relabelled at the end.
+func (g *Graph) Tarjan() *SCC {
+	n := g.maxVertex
+	index := make([]int, n+1) // Discovery index, starting at 1; 0 means unvisited.
+	lowLink := make([]int, n+1)
+	onStack := make([]bool, n+1)
+	membership := make([]int, n+1)
+	components := make([]int, 0) // The component stack.
+	count, time := 0, 0
+
+	type frame struct {
+		vertex, next int
+	}
+	stack := make([]frame, 0)
+	discover := func(v int) {
+		time++
+		index[v], lowLink[v] = time, time
+		onStack[v] = true
+		components = append(components, v)
+		stack = append(stack, frame{v, 0})
+	}
 
-	// First pass: Fill the stack with finishing times.
-	for node := 1; node <= maxVertex; node++ {
-		if !visited[node] {
-			g.dfs(node, visited, &stack)
+	for root := 1; root <= n; root++ {
+		if index[root] != 0 {
+			continue
+		}
+		discover(root)
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			v := top.vertex
+			if top.next < len(g.adj[v]) {
+				w := g.adj[v][top.next]
+				top.next++
+				if index[w] == 0 {
+					discover(w)
+				} else if onStack[w] {
+					lowLink[v] = min(lowLink[v], index[w])
+				}
+				continue
+			}
+
+			// v is finished: close its component if it is a root, then pass its low-link to the parent.
+			stack = stack[:len(stack)-1]
+			if lowLink[v] == index[v] {
+				count++
+				for {
+					w := components[len(components)-1]
+					components = components[:len(components)-1]
+					onStack[w] = false
+					membership[w] = count
+					if w == v {
+						break
+					}
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1].vertex
+				lowLink[parent] = min(lowLink[parent], lowLink[v])
+			}
 		}
 	}
 
-	// Second pass: Find SCCs in the reversed graph.
-	visited = make([]bool, maxVertex+1)
-	sccSizes := make([]int, 0) // Use make() instead of []int{}.
-	for i := len(stack) - 1; i >= 0; i-- {
-		node := stack[i]
-		if !visited[node] {
-			size := g.reverseDFS(node, visited)
-			sccSizes = append(sccSizes, size)
+	// Reverse the labels so that they follow a topological order of the condensation.
+	for v := 1; v <= n; v++ {
+		membership[v] = count + 1 - membership[v]
+	}
+	return newSCC(membership, count)
+}
+
+// intHeap is a min-heap of vertices.
+type intHeap []int
+
+func (h intHeap) Len() int           { return len(h) }
+func (h intHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intHeap) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+// TopologicalSort orders the vertices so that every edge goes from an earlier to a later vertex, using
+// Kahn's algorithm with the smallest ready vertex first. It returns an error if the graph has a cycle.
+func (g *Graph) TopologicalSort() ([]int, error) {
+	inDegree := make([]int, g.maxVertex+1)
+	for v := 1; v <= g.maxVertex; v++ {
+		inDegree[v] = len(g.revAdj[v])
+	}
+	ready := &intHeap{}
+	for v := 1; v <= g.maxVertex; v++ {
+		if inDegree[v] == 0 {
+			*ready = append(*ready, v) // Increasing order is already a valid heap.
 		}
 	}
 
-	// Sort SCC sizes in descending order.
-	sort.Slice(sccSizes, func(i, j int) bool {
-		return sccSizes[i] > sccSizes[j]
-	})
-	return sccSizes
+	order := make([]int, 0, g.maxVertex)
+	for ready.Len() > 0 {
+		v := heap.Pop(ready).(int)
+		order = append(order, v)
+		for _, w := range g.adj[v] {
+			inDegree[w]--
+			if inDegree[w] == 0 {
+				heap.Push(ready, w)
+			}
+		}
+	}
+	if len(order) < g.maxVertex {
+		return nil, errors.New("graph contains a cycle, topological sort not possible")
+	}
+	return order, nil
 }
 
-func main() {
-	// Open the input file.
-	file, err := os.Open("course_2/module_1/programming_assignment_1/SCC.txt")
+// readGraph parses an edge list with one "from to" pair per line.
+func readGraph(path string) (*Graph, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return
+		return nil, err
 	}
 	// Handle file close error with defer.
 	defer func() {
@@ -99,7 +281,6 @@ func main() {
 	scanner := bufio.NewScanner(file)
 
 	// Parse the input file and add edges to the graph.
-	maxVertex := 0
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.Fields(line)
@@ -117,21 +298,114 @@ func main() {
 			continue
 		}
 		graph.addEdge(from, to)
-		if from > maxVertex {
-			maxVertex = from
+	}
+	return graph, scanner.Err()
+}
+
+// sccByReachability finds the components of a small graph from its transitive closure, for checking.
+// Two vertices share a component exactly when each reaches the other; components are represented by their smallest vertex.
+func sccByReachability(g *Graph) []int {
+	n := g.maxVertex
+	reach := make([][]bool, n+1)
+	for v := 1; v <= n; v++ {
+		reach[v] = make([]bool, n+1)
+		reach[v][v] = true
+		for _, w := range g.adj[v] {
+			reach[v][w] = true
+		}
+	}
+	for k := 1; k <= n; k++ {
+		for i := 1; i <= n; i++ {
+			for j := 1; j <= n; j++ {
+				reach[i][j] = reach[i][j] || reach[i][k] && reach[k][j]
+			}
+		}
+	}
+	representative := make([]int, n+1)
+	for v := 1; v <= n; v++ {
+		for u := 1; u <= v; u++ {
+			if reach[u][v] && reach[v][u] {
+				representative[v] = u
+				break
+			}
+		}
+	}
+	return representative
+}
+
+// isValidSCC checks the components against the expected representatives and checks that the
+// condensation is acyclic with edges only from smaller to larger labels.
+func isValidSCC(g *Graph, scc *SCC, representative []int) bool {
+	for v := 1; v <= g.maxVertex; v++ {
+		if scc.Components[scc.Membership[v]][0] != representative[v] {
+			return false
+		}
+	}
+	condensation := scc.Condensation(g)
+	for c, targets := range condensation.adj {
+		for _, d := range targets {
+			if d <= c {
+				return false
+			}
+		}
+	}
+	_, err := condensation.TopologicalSort()
+	return err == nil
+}
+
+// verifyAgainstReachability checks Tarjan and Kosaraju on random small graphs against the transitive closure
+// and returns an error describing the first graph on which either disagrees.
+func verifyAgainstReachability(graphs int) error {
+	for i := 0; i < graphs; i++ {
+		n := 1 + rand.Intn(30)
+		graph := newGraph()
+		graph.maxVertex = n
+		for edges := rand.Intn(3 * n); edges > 0; edges-- {
+			graph.addEdge(1+rand.Intn(n), 1+rand.Intn(n))
 		}
-		if to > maxVertex {
-			maxVertex = to
+		representative := sccByReachability(graph)
+		if !isValidSCC(graph, graph.Tarjan(), representative) {
+			return fmt.Errorf("graph %d with %d vertices: Tarjan's components differ from the transitive closure", i+1, n)
+		}
+		if !isValidSCC(graph, graph.Kosaraju(), representative) {
+			return fmt.Errorf("graph %d with %d vertices: Kosaraju's components differ from the transitive closure", i+1, n)
 		}
 	}
+	return nil
+}
 
-	if err := scanner.Err(); err != nil {
+func main() {
+	algorithm := flag.String("algorithm", "kosaraju", "algorithm to run: kosaraju or tarjan")
+	input := flag.String("input", "course_2/module_1/programming_assignment_1/SCC.txt", "edge list file with one \"from to\" pair per line")
+	verify := flag.Bool("verify", false, "check both algorithms against the transitive closure of random graphs")
+	flag.Parse()
+
+	if *verify {
+		if err := verifyAgainstReachability(200); err != nil {
+			fmt.Println("Mismatch:", err)
+			os.Exit(1)
+		}
+		fmt.Println("Random graphs: Tarjan and Kosaraju match the transitive closure on all 200")
+		return
+	}
+
+	graph, err := readGraph(*input)
+	if err != nil {
 		fmt.Println("Error reading file:", err)
 		return
 	}
 
-	// Run Kosaraju's algorithm.
-	sccSizes := graph.kosaraju(maxVertex)
+	var scc *SCC
+	switch *algorithm {
+	case "kosaraju":
+		scc = graph.Kosaraju()
+	case "tarjan":
+		scc = graph.Tarjan()
+	default:
+		fmt.Println("Error: unknown algorithm", *algorithm)
+		os.Exit(1)
+	}
+	sccSizes := scc.Sizes()
 
 	// Output the sizes of the 5 largest SCCs.
 	for i := 0; i < 5; i++ {
@@ -145,4 +419,18 @@ func main() {
 		}
 	}
 	fmt.Println()
+
+	// The condensation is a DAG; its labels already follow a topological order.
+	condensation := scc.Condensation(graph)
+	order, err := condensation.TopologicalSort()
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	edges := 0
+	for _, targets := range condensation.adj {
+		edges += len(targets)
+	}
+	fmt.Printf("Condensation: %d components, %d edges, topological order starts %v\n",
+		scc.Count(), edges, order[:min(5, len(order))])
 }
@@ -4,6 +4,8 @@ import (
 	"container/heap"
 	"fmt"
 	"math"
+	"math/rand"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -50,13 +52,15 @@ func (pq *PriorityQueue) Pop() any {
 
 // Graph represents a weighted graph.
 type Graph struct {
-	Edges map[string]map[string]int // Adjacency list to store edges and their weights.
+	Edges   map[string]map[string]int // Adjacency list to store edges and their weights.
+	reverse map[string]map[string]int // The same edges reversed, for searches towards a target.
 }
 
 // NewGraph creates and initializes a new Graph.
 func NewGraph() *Graph {
 	return &Graph{
-		Edges: make(map[string]map[string]int),
+		Edges:   make(map[string]map[string]int),
+		reverse: make(map[string]map[string]int),
 	}
 }
 
@@ -64,6 +68,7 @@ func NewGraph() *Graph {
 func (g *Graph) AddNode(node string) {
 	if _, exists := g.Edges[node]; !exists {
 		g.Edges[node] = make(map[string]int) // Initialize an empty adjacency list.
+		g.reverse[node] = make(map[string]int)
 	}
 }
 
@@ -72,6 +77,7 @@ func (g *Graph) AddEdge(from, to string, weight int) {
 	g.AddNode(from)
 	g.AddNode(to)
 	g.Edges[from][to] = weight // Add the edge with its weight.
+	g.reverse[to][from] = weight
 }
 
 // Dijkstra computes shortest paths from the start node to all other nodes.
@@ -123,6 +129,247 @@ func ReconstructPath(previous map[string]string, start, target string) []string
 	return path
 }
 
+// QueryResult is the answer to a single source-target shortest-path query.
+type QueryResult struct {
+	Distance int      // Length of the shortest path, or math.MaxInt if the target is unreachable.
+	Path     []string // The shortest path in the format of ReconstructPath; empty if the target is unreachable.
+	Settled  int      // Number of nodes removed from the queue with their final distance.
+}
+
+// Heuristic estimates the distance from a node to the target of an A* query.
+// It must be admissible, never overestimating the true distance, for A* to return shortest paths.
+type Heuristic func(node string) int
+
+// ZeroHeuristic estimates every distance as 0, which makes A* Dijkstra's algorithm stopped at the target.
+func ZeroHeuristic(string) int { return 0 }
+
+// Point is the position of a node in the plane.
+type Point struct {
+	X, Y float64
+}
+
+// EuclideanHeuristic estimates the distance to the target as the straight-line distance, rounded down.
+// It is admissible when no edge is shorter than the straight-line distance between its endpoints.
+// Nodes without a position are estimated as 0.
+func EuclideanHeuristic(positions map[string]Point, target string) Heuristic {
+	goal, ok := positions[target]
+	return func(node string) int {
+		position, known := positions[node]
+		if !ok || !known {
+			return 0
+		}
+		return int(math.Hypot(position.X-goal.X, position.Y-goal.Y))
+	}
+}
+
+// Landmarks holds the shortest distances from and to a few landmark nodes for the ALT heuristic
+// (A*, landmarks and the triangle inequality).
+type Landmarks struct {
+	from []map[string]int // from[i][v] is the distance from landmark i to v.
+	to   []map[string]int // to[i][v] is the distance from v to landmark i.
+}
+
+// NewLandmarks precomputes the distances from and to every landmark with two Dijkstra runs per landmark.
+// Landmarks at the edge of the graph, far from each other, give the best estimates.
+func NewLandmarks(g *Graph, landmarks []string) *Landmarks {
+	reversed := &Graph{Edges: g.reverse, reverse: g.Edges}
+	l := &Landmarks{}
+	for _, landmark := range landmarks {
+		from, _ := g.Dijkstra(landmark)
+		to, _ := reversed.Dijkstra(landmark)
+		l.from = append(l.from, from)
+		l.to = append(l.to, to)
+	}
+	return l
+}
+
+// Heuristic returns the ALT estimate of the distance to the target. By the triangle inequality, for every
+// landmark L both d(L, t) - d(L, v) and d(v, L) - d(t, L) are lower bounds on d(v, t); the estimate is the
+// largest of them. It is admissible and consistent for any non-negative weights.
+func (l *Landmarks) Heuristic(target string) Heuristic {
+	return func(node string) int {
+		estimate := 0
+		for i := range l.from {
+			if fromNode, fromTarget := l.from[i][node], l.from[i][target]; fromNode != math.MaxInt && fromTarget != math.MaxInt {
+				estimate = max(estimate, fromTarget-fromNode)
+			}
+			if toNode, toTarget := l.to[i][node], l.to[i][target]; toNode != math.MaxInt && toTarget != math.MaxInt {
+				estimate = max(estimate, toNode-toTarget)
+			}
+		}
+		return estimate
+	}
+}
+
+// AStar finds a shortest path from start to target, expanding nodes in order of their distance from the
+// start plus the heuristic estimate of their distance to the target. The search stops as soon as the target
+// is settled; a good heuristic steers it towards the target and settles far fewer nodes than Dijkstra's algorithm.
+func (g *Graph) AStar(start, target string, heuristic Heuristic) QueryResult {
+	result := QueryResult{Distance: math.MaxInt, Path: []string{}}
+	if _, exists := g.Edges[start]; !exists {
+		return result
+	}
+
+	distances := map[string]int{start: 0}
+	previous := make(map[string]string)
+	pq := &PriorityQueue{}
+	heap.Push(pq, &Item{Node: start, Priority: heuristic(start)})
+
+	for pq.Len() > 0 {
+		current := heap.Pop(pq).(*Item)
+		distance := distances[current.Node]
+		if current.Priority != distance+heuristic(current.Node) {
+			continue // A stale entry: the node was queued again with a shorter distance.
+		}
+		result.Settled++
+		if current.Node == target {
+			result.Distance = distance
+			result.Path = ReconstructPath(previous, start, target)
+			return result
+		}
+
+		for neighbor, weight := range g.Edges[current.Node] {
+			newDistance := distance + weight
+			if old, seen := distances[neighbor]; !seen || newDistance < old {
+				distances[neighbor] = newDistance
+				previous[neighbor] = current.Node
+				heap.Push(pq, &Item{Node: neighbor, Priority: newDistance + heuristic(neighbor)})
+			}
+		}
+	}
+	return result
+}
+
+// searchFrontier is one direction of a bidirectional search: a Dijkstra search over the given edges.
+type searchFrontier struct {
+	edges     map[string]map[string]int
+	distances map[string]int
+	previous  map[string]string
+	queue     *PriorityQueue
+}
+
+// newSearchFrontier starts a search from the source node.
+func newSearchFrontier(edges map[string]map[string]int, source string) *searchFrontier {
+	f := &searchFrontier{
+		edges:     edges,
+		distances: map[string]int{source: 0},
+		previous:  make(map[string]string),
+		queue:     &PriorityQueue{},
+	}
+	heap.Push(f.queue, &Item{Node: source, Priority: 0})
+	return f
+}
+
+// top drops stale entries and returns the smallest distance in the queue, or math.MaxInt if it is empty.
+func (f *searchFrontier) top() int {
+	for f.queue.Len() > 0 {
+		item := (*f.queue)[0]
+		if item.Priority == f.distances[item.Node] {
+			return item.Priority
+		}
+		heap.Pop(f.queue)
+	}
+	return math.MaxInt
+}
+
+// BidirectionalDijkstra finds a shortest path from start to target by searching forwards from the start
+// and backwards from the target, always advancing the side whose next node is closer. Every time a node
+// gets a distance from both sides, the path through it is a candidate. The search stops once the two
+// queue minimums add up to at least the best candidate: any path not yet seen would have to be longer.
+// Stopping when the first node is settled from both sides is not enough, since the shortest path may
+// run through a node that only one side has settled.
+func (g *Graph) BidirectionalDijkstra(start, target string) QueryResult {
+	result := QueryResult{Distance: math.MaxInt, Path: []string{}}
+	_, startExists := g.Edges[start]
+	_, targetExists := g.Edges[target]
+	if !startExists || !targetExists {
+		return result
+	}
+
+	forward := newSearchFrontier(g.Edges, start)
+	backward := newSearchFrontier(g.reverse, target)
+	best, meeting := math.MaxInt, ""
+	if start == target {
+		best, meeting = 0, start
+	}
+
+	for {
+		forwardTop, backwardTop := forward.top(), backward.top()
+		if forwardTop == math.MaxInt || backwardTop == math.MaxInt || forwardTop+backwardTop >= best {
+			break
+		}
+
+		// Settle the closer of the two next nodes.
+		side, other := forward, backward
+		if backwardTop < forwardTop {
+			side, other = backward, forward
+		}
+		current := heap.Pop(side.queue).(*Item)
+		result.Settled++
+
+		for neighbor, weight := range side.edges[current.Node] {
+			newDistance := current.Priority + weight
+			if old, seen := side.distances[neighbor]; seen && newDistance >= old {
+				continue
+			}
+			side.distances[neighbor] = newDistance
+			side.previous[neighbor] = current.Node
+			heap.Push(side.queue, &Item{Node: neighbor, Priority: newDistance})
+			if otherDistance, seen := other.distances[neighbor]; seen && newDistance+otherDistance < best {
+				best, meeting = newDistance+otherDistance, neighbor
+			}
+		}
+	}
+	if meeting == "" {
+		return result
+	}
+
+	// Join the forward path to the meeting node with the backward path from it.
+	result.Distance = best
+	result.Path = ReconstructPath(forward.previous, start, meeting)
+	toTarget := ReconstructPath(backward.previous, target, meeting)
+	slices.Reverse(toTarget)
+	result.Path = append(result.Path, toTarget[1:]...)
+	return result
+}
+
+// pathLength returns the total weight of a path, or -1 if it uses a missing edge.
+func (g *Graph) pathLength(path []string) int {
+	length := 0
+	for i := 1; i < len(path); i++ {
+		weight, exists := g.Edges[path[i-1]][path[i]]
+		if !exists {
+			return -1
+		}
+		length += weight
+	}
+	return length
+}
+
+// gridRoadNetwork builds a size x size grid of intersections, 10 units apart, joined by roads in both directions.
+// Each road is 10 to 29 units long, so the straight-line distance between positions never overestimates.
+func gridRoadNetwork(size int, rng *rand.Rand) (*Graph, map[string]Point) {
+	graph := NewGraph()
+	positions := make(map[string]Point)
+	name := func(row, column int) string { return fmt.Sprintf("%d,%d", row, column) }
+	for row := 0; row < size; row++ {
+		for column := 0; column < size; column++ {
+			positions[name(row, column)] = Point{X: float64(10 * column), Y: float64(10 * row)}
+			if column+1 < size {
+				weight := 10 + rng.Intn(20)
+				graph.AddEdge(name(row, column), name(row, column+1), weight)
+				graph.AddEdge(name(row, column+1), name(row, column), weight)
+			}
+			if row+1 < size {
+				weight := 10 + rng.Intn(20)
+				graph.AddEdge(name(row, column), name(row+1, column), weight)
+				graph.AddEdge(name(row+1, column), name(row, column), weight)
+			}
+		}
+	}
+	return graph, positions
+}
+
 // joinPath formats a slice of nodes into a string path like "A -> B -> C".
 func joinPath(path []string) string {
 	return strings.Join(path, " -> ")
@@ -179,4 +426,56 @@ func main() {
 			fmt.Printf("To %s: No path found\n", node)
 		}
 	}
+
+	// Single-pair queries only explore as much of the graph as they need.
+	fmt.Println("\nQueries from A to E:")
+	for _, query := range []struct {
+		name   string
+		result QueryResult
+	}{
+		{"Bidirectional Dijkstra", graph.BidirectionalDijkstra("A", "E")},
+		{"A* (zero heuristic)", graph.AStar("A", "E", ZeroHeuristic)},
+	} {
+		fmt.Printf("%s: %s, distance %d, %d nodes settled\n",
+			query.name, joinPath(query.result.Path), query.result.Distance, query.result.Settled)
+	}
+	if result := graph.BidirectionalDijkstra("E", "A"); len(result.Path) == 0 {
+		fmt.Println("From E to A: No path found")
+	}
+
+	// A road network: compare the nodes settled by each query across the grid.
+	rng := rand.New(rand.NewSource(1))
+	roads, positions := gridRoadNetwork(40, rng)
+	landmarks := NewLandmarks(roads, []string{"0,0", "0,39", "39,0", "39,39"})
+	from, to := "5,2", "33,36"
+	fmt.Printf("\nRoad network with %d intersections, from %s to %s:\n", len(roads.Edges), from, to)
+	for _, query := range []struct {
+		name   string
+		result QueryResult
+	}{
+		{"Dijkstra stopped at the target", roads.AStar(from, to, ZeroHeuristic)},
+		{"Bidirectional Dijkstra", roads.BidirectionalDijkstra(from, to)},
+		{"A* (Euclidean)", roads.AStar(from, to, EuclideanHeuristic(positions, to))},
+		{"A* (ALT, 4 landmarks)", roads.AStar(from, to, landmarks.Heuristic(to))},
+	} {
+		fmt.Printf("%-31s distance %d, %d hops, %d nodes settled\n",
+			query.name, query.result.Distance, len(query.result.Path)-1, query.result.Settled)
+	}
+
+	// Every query agrees with the full single-source computation.
+	matches := true
+	for i := 0; i < 50; i++ {
+		from := fmt.Sprintf("%d,%d", rng.Intn(40), rng.Intn(40))
+		to := fmt.Sprintf("%d,%d", rng.Intn(40), rng.Intn(40))
+		distances, _ := roads.Dijkstra(from)
+		for _, result := range []QueryResult{
+			roads.BidirectionalDijkstra(from, to),
+			roads.AStar(from, to, EuclideanHeuristic(positions, to)),
+			roads.AStar(from, to, landmarks.Heuristic(to)),
+		} {
+			matches = matches && result.Distance == distances[to] && roads.pathLength(result.Path) == distances[to] &&
+				result.Path[0] == from && result.Path[len(result.Path)-1] == to
+		}
+	}
+	fmt.Println("Queries match Dijkstra:", matches)
 }